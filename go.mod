@@ -4,9 +4,12 @@ go 1.17
 
 require (
 	github.com/alexflint/go-arg v1.4.2
+	github.com/creack/pty v1.1.17
 	github.com/google/uuid v1.1.2
 	github.com/sirupsen/logrus v1.8.1
+	github.com/vishvananda/netlink v1.1.0
 	golang.org/x/sys v0.0.0-20211112193437-faf0a1b62c6b
+	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
 	google.golang.org/grpc v1.42.0
 	google.golang.org/protobuf v1.27.1
 )
@@ -14,6 +17,7 @@ require (
 require (
 	github.com/alexflint/go-scalar v1.0.0 // indirect
 	github.com/golang/protobuf v1.5.0 // indirect
+	github.com/vishvananda/netns v0.0.0-20191106174202-0a2b9b5464df // indirect
 	golang.org/x/net v0.0.0-20200822124328-c89045814202 // indirect
 	golang.org/x/text v0.3.0 // indirect
 	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013 // indirect
@@ -23,6 +23,9 @@ type JobsClient interface {
 	Status(ctx context.Context, in *StatusInput, opts ...grpc.CallOption) (*StatusResponse, error)
 	Monitor(ctx context.Context, in *MonitorJobInput, opts ...grpc.CallOption) (Jobs_MonitorClient, error)
 	List(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ListJobsResponse, error)
+	Attach(ctx context.Context, opts ...grpc.CallOption) (Jobs_AttachClient, error)
+	Usage(ctx context.Context, in *UsageInput, opts ...grpc.CallOption) (*UsageResponse, error)
+	UpdateLimits(ctx context.Context, in *UpdateLimitsInput, opts ...grpc.CallOption) (*UpdateLimitsResponse, error)
 }
 
 type jobsClient struct {
@@ -101,6 +104,55 @@ func (c *jobsClient) List(ctx context.Context, in *Empty, opts ...grpc.CallOptio
 	return out, nil
 }
 
+func (c *jobsClient) Attach(ctx context.Context, opts ...grpc.CallOption) (Jobs_AttachClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Jobs_ServiceDesc.Streams[1], "/Jobs/Attach", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &jobsAttachClient{stream}
+	return x, nil
+}
+
+type Jobs_AttachClient interface {
+	Send(*AttachInput) error
+	Recv() (*MonitorJobResponse, error)
+	grpc.ClientStream
+}
+
+type jobsAttachClient struct {
+	grpc.ClientStream
+}
+
+func (x *jobsAttachClient) Send(m *AttachInput) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *jobsAttachClient) Recv() (*MonitorJobResponse, error) {
+	m := new(MonitorJobResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *jobsClient) Usage(ctx context.Context, in *UsageInput, opts ...grpc.CallOption) (*UsageResponse, error) {
+	out := new(UsageResponse)
+	err := c.cc.Invoke(ctx, "/Jobs/Usage", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *jobsClient) UpdateLimits(ctx context.Context, in *UpdateLimitsInput, opts ...grpc.CallOption) (*UpdateLimitsResponse, error) {
+	out := new(UpdateLimitsResponse)
+	err := c.cc.Invoke(ctx, "/Jobs/UpdateLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // JobsServer is the server API for Jobs service.
 // All implementations must embed UnimplementedJobsServer
 // for forward compatibility
@@ -110,6 +162,9 @@ type JobsServer interface {
 	Status(context.Context, *StatusInput) (*StatusResponse, error)
 	Monitor(*MonitorJobInput, Jobs_MonitorServer) error
 	List(context.Context, *Empty) (*ListJobsResponse, error)
+	Attach(Jobs_AttachServer) error
+	Usage(context.Context, *UsageInput) (*UsageResponse, error)
+	UpdateLimits(context.Context, *UpdateLimitsInput) (*UpdateLimitsResponse, error)
 	mustEmbedUnimplementedJobsServer()
 }
 
@@ -132,6 +187,15 @@ func (UnimplementedJobsServer) Monitor(*MonitorJobInput, Jobs_MonitorServer) err
 func (UnimplementedJobsServer) List(context.Context, *Empty) (*ListJobsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
 }
+func (UnimplementedJobsServer) Attach(Jobs_AttachServer) error {
+	return status.Errorf(codes.Unimplemented, "method Attach not implemented")
+}
+func (UnimplementedJobsServer) Usage(context.Context, *UsageInput) (*UsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Usage not implemented")
+}
+func (UnimplementedJobsServer) UpdateLimits(context.Context, *UpdateLimitsInput) (*UpdateLimitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLimits not implemented")
+}
 func (UnimplementedJobsServer) mustEmbedUnimplementedJobsServer() {}
 
 // UnsafeJobsServer may be embedded to opt out of forward compatibility for this service.
@@ -238,6 +302,68 @@ func _Jobs_List_Handler(srv interface{}, ctx context.Context, dec func(interface
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Jobs_Attach_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(JobsServer).Attach(&jobsAttachServer{stream})
+}
+
+type Jobs_AttachServer interface {
+	Send(*MonitorJobResponse) error
+	Recv() (*AttachInput, error)
+	grpc.ServerStream
+}
+
+type jobsAttachServer struct {
+	grpc.ServerStream
+}
+
+func (x *jobsAttachServer) Send(m *MonitorJobResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *jobsAttachServer) Recv() (*AttachInput, error) {
+	m := new(AttachInput)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Jobs_Usage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsageInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).Usage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Jobs/Usage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).Usage(ctx, req.(*UsageInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Jobs_UpdateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLimitsInput)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(JobsServer).UpdateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/Jobs/UpdateLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(JobsServer).UpdateLimits(ctx, req.(*UpdateLimitsInput))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Jobs_ServiceDesc is the grpc.ServiceDesc for Jobs service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -261,6 +387,14 @@ var Jobs_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "List",
 			Handler:    _Jobs_List_Handler,
 		},
+		{
+			MethodName: "Usage",
+			Handler:    _Jobs_Usage_Handler,
+		},
+		{
+			MethodName: "UpdateLimits",
+			Handler:    _Jobs_UpdateLimits_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -268,6 +402,12 @@ var Jobs_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _Jobs_Monitor_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "Attach",
+			Handler:       _Jobs_Attach_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "job_service.proto",
 }
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"os"
+	"time"
+
+	"github.com/bill-rich/rjob/lib/cgroup"
+	"github.com/bill-rich/rjob/lib/command"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+)
+
+// reapInterval is how often ended jobs are checked for cgroups that can be
+// deleted, and for records that have expired.
+const reapInterval = 10 * time.Second
+
+// reapCgroup deletes the cgroup of an ended job once all of its processes have
+// exited. The final resource usage of the job is saved before the cgroup is
+// deleted. Jobs whose cgroup could not be created have nothing to reap.
+func (server *ApiServer) reapCgroup(jobId string, job *command.JobConfig) {
+	if job.CgroupConfig == nil {
+		return
+	}
+	if _, err := os.Stat(job.CgroupConfig.Path); os.IsNotExist(err) {
+		return
+	}
+
+	for {
+		empty, err := job.CgroupConfig.IsEmpty()
+		if err != nil {
+			log.Errorf("Unable to reap cgroup of job (%s): %s", jobId, err)
+			return
+		}
+		if empty {
+			break
+		}
+		time.Sleep(reapInterval)
+	}
+
+	if err := job.UpdateUsage(); err != nil {
+		log.Errorf("Unable to read final usage of job (%s): %s", jobId, err)
+	}
+	server.saveJob(jobId, job)
+
+	if err := job.CgroupConfig.Delete(); err != nil {
+		log.Errorf("Unable to reap cgroup of job (%s): %s", jobId, err)
+	}
+}
+
+// RemoveOrphanedCgroups kills and deletes the cgroups of jobs that are not
+// running, such as those left behind by a previous run of the server. Only
+// cgroups named after a job ID are considered.
+func (server *ApiServer) RemoveOrphanedCgroups() error {
+	return cgroup.RemoveOrphans(server.CgroupParent, func(name string) bool {
+		if _, err := uuid.Parse(name); err != nil {
+			return false
+		}
+		server.jobsLock.RLock()
+		defer server.jobsLock.RUnlock()
+		job, ok := server.Jobs[name]
+		return !ok || !job.IsRunning()
+	})
+}
+
+// ReapJobs removes jobs that ended more than retention ago, along with their
+// saved output. It never returns, unless retention is zero, in which case jobs
+// are kept forever.
+func (server *ApiServer) ReapJobs(retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	for range time.Tick(reapInterval) {
+		server.removeExpiredJobs(time.Now().Add(-retention))
+	}
+}
+
+// removeExpiredJobs removes jobs that ended before cutoff. Jobs that were lost
+// have no end time, so they expire based on their start time. Jobs are removed
+// from the store once they are gone from Jobs, so that they are not saved again.
+func (server *ApiServer) removeExpiredJobs(cutoff time.Time) {
+	expired := []string{}
+	server.jobsLock.Lock()
+	for id, job := range server.Jobs {
+		state := job.State()
+		if state.Status == command.JobStatusPending || state.Status == command.JobStatusRunning {
+			continue
+		}
+		ended := state.EndTime
+		if ended.IsZero() {
+			ended = state.StartTime
+		}
+		if ended.After(cutoff) {
+			continue
+		}
+
+		delete(server.Jobs, id)
+		expired = append(expired, id)
+	}
+	server.jobsLock.Unlock()
+
+	server.storeLock.Lock()
+	defer server.storeLock.Unlock()
+	for _, id := range expired {
+		if server.Store != nil {
+			if err := server.Store.Delete(id); err != nil {
+				log.Errorf("Unable to remove expired job (%s) from the job store: %s", id, err)
+			}
+		}
+		log.Debugf("Removed expired job (%s)", id)
+	}
+}
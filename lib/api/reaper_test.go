@@ -0,0 +1,52 @@
+// +build unit
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bill-rich/rjob/lib/command"
+	"github.com/bill-rich/rjob/lib/store"
+)
+
+func TestRemoveExpiredJobsSavedLate(t *testing.T) {
+	jobStore, err := store.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &ApiServer{
+		Jobs:  map[string]*command.JobConfig{},
+		Store: jobStore,
+	}
+
+	jobId := "01e3f153-f55c-43a5-98d1-1a6b1e29ad86"
+	job := &command.JobConfig{
+		Command:   "true",
+		Output:    &command.OutputBuffer{},
+		ErrOutput: &command.OutputBuffer{},
+		JobState: command.JobState{
+			Status:    command.JobStatusExited,
+			StartTime: time.Now().Add(-2 * time.Hour),
+			EndTime:   time.Now().Add(-time.Hour),
+		},
+	}
+	server.addJob(jobId, job)
+	server.saveJob(jobId, job)
+
+	server.removeExpiredJobs(time.Now())
+	// The wait goroutine of the job saves it once more after its cgroup has
+	// been reaped.
+	server.saveJob(jobId, job)
+
+	if _, ok := server.Jobs[jobId]; ok {
+		t.Errorf("expected job to be removed")
+	}
+	records, err := jobStore.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no saved jobs, got: %d", len(records))
+	}
+}
@@ -1,22 +1,73 @@
 package api
 
 import (
+	"bytes"
 	context "context"
 	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/bill-rich/rjob/lib/cgroup"
 	"github.com/bill-rich/rjob/lib/command"
+	"github.com/bill-rich/rjob/lib/network"
+	"github.com/bill-rich/rjob/lib/policy"
+	"github.com/bill-rich/rjob/lib/rootfs"
+	"github.com/bill-rich/rjob/lib/security"
+	"github.com/bill-rich/rjob/lib/store"
 	"github.com/google/uuid"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// stopTimeout is how long Stop waits for a job to end after it was killed.
+const stopTimeout = 5 * time.Second
+
+// defaultGracePeriod is how long Stop waits for a job to end on its own before
+// killing it, when the client does not choose a grace period.
+const defaultGracePeriod = 10 * time.Second
+
 type ApiServer struct {
 	UnimplementedJobsServer
-	Jobs map[string]command.JobConfig
+	Jobs     map[string]*command.JobConfig
+	jobsLock sync.RWMutex
+
+	// Store keeps jobs across restarts of the server. Jobs are only kept in
+	// memory when it is nil.
+	Store store.JobStore
+	// storeLock is held while a job is saved or removed, so that an older
+	// state of a job never replaces a newer one in the store, and removed
+	// jobs are never saved again. It is never taken while holding jobsLock.
+	storeLock sync.Mutex
+	// OutputLimits bound the output kept for each stream of a job. Output
+	// that does not fit in memory is kept in the store.
+	OutputLimits command.OutputLimits
+
+	// CgroupParent is the cgroup under which the cgroups of jobs are
+	// created.
+	CgroupParent string
+	// Controllers are the cgroup controllers available to jobs. All limits
+	// are assumed to be enforceable when it is nil.
+	Controllers cgroup.Controllers
+	// DegradeLimits starts jobs without the limits that cannot be enforced,
+	// instead of refusing them.
+	DegradeLimits bool
+	// Rootless runs jobs in user namespaces, so the server does not need to
+	// run as root.
+	Rootless bool
+	// Policy restricts what clients may ask for when they start jobs.
+	Policy *policy.Policy
+	// ProfileDir holds the security profiles jobs may use.
+	ProfileDir string
+	// Bridge connects jobs using network.ModeBridge to the host. Bridge
+	// networking is unavailable when it is nil.
+	Bridge *network.Bridge
 }
 
 func (server *ApiServer) Start(ctx context.Context, input *StartJobInput) (*StartJobResponse, error) {
@@ -24,61 +75,149 @@ func (server *ApiServer) Start(ctx context.Context, input *StartJobInput) (*Star
 	log.Debugf("New job requested. Id: %s", jobId)
 
 	user := getUserFromContext(ctx)
+	namespaces, err := server.namespaces(user, input.Namespaces, input.Network)
+	if err != nil {
+		log.Infof("Namespaces of job %s refused: %s", jobId, err)
+		return nil, err
+	}
+	ports, err := server.checkNetwork(input.Network, input.Ports)
+	if err != nil {
+		log.Infof("Network of job %s refused: %s", jobId, err)
+		return nil, err
+	}
+	jobRootfs, err := server.rootfs(user, input.Rootfs, input.Mounts, namespaces)
+	if err != nil {
+		log.Infof("Root filesystem of job %s refused: %s", jobId, err)
+		return nil, err
+	}
+	credential, err := server.Policy.For(user).Credential(input.User, input.Group, input.Groups)
+	if err != nil {
+		log.Infof("Identity of job %s refused: %s", jobId, err)
+		return nil, err
+	}
+	profile, err := server.profile(user, input.Profile)
+	if err != nil {
+		log.Infof("Profile of job %s refused: %s", jobId, err)
+		return nil, err
+	}
 
-	job := command.JobConfig{
+	job := &command.JobConfig{
 		Command:    input.Command,
 		Args:       input.Args,
 		Owner:      user,
+		Tty:        input.Tty,
+		OpenStdin:  input.Stdin,
+		Namespaces: namespaces,
+		Rootless:   server.Rootless,
+		Network:    input.Network,
+		Rootfs:     jobRootfs,
+		Credential: credential,
+		Profile:    profile,
 		CgroupName: jobId.String(),
 		CgroupConfig: &cgroup.CgroupConfig{
-			Name:   jobId.String(),
-			Cpu:    int(input.Cpu),
-			Memory: int(input.Memory),
-			Io:     int(input.Blkio),
-			Path:   filepath.Join(cgroup.CgroupMountDir, jobId.String()),
+			Name:       jobId.String(),
+			Cpu:        int(input.Cpu),
+			Memory:     int(input.Memory),
+			MemoryHigh: int(input.MemoryHigh),
+			MemorySwap: int(input.MemorySwap),
+			Io:         int(input.Blkio),
+			Pids:       int(input.Pids),
+			CpusetCpus: input.CpusetCpus,
+			CpusetMems: input.CpusetMems,
+			IoMax:      ioMax(input.IoMax),
+			Path:       filepath.Join(server.CgroupParent, jobId.String()),
 		},
 	}
-	if err := job.Start(); err != nil {
-		log.Infof("Unable to run job %s: %s", jobId, err)
+	if err := job.CgroupConfig.Validate(); err != nil {
+		log.Infof("Invalid limits for job %s: %s", jobId, err)
+		return nil, fmt.Errorf("invalid limits: %s", err)
+	}
+	if err := server.checkLimits(job.CgroupConfig); err != nil {
+		log.Infof("Unsupported limits for job %s: %s", jobId, err)
 		return nil, err
 	}
+	if input.Network == network.ModeBridge {
+		job.SetupNetwork = func(pid int) (network.Config, error) {
+			return server.Bridge.Attach(jobId.String(), pid, ports)
+		}
+	}
+	if err := server.outputBuffers(jobId.String(), job, input.Timestamps); err != nil {
+		log.Infof("Unable to save output of job %s: %s", jobId, err)
+		return nil, err
+	}
+
+	// Jobs that fail to start are kept, so the error can be looked up later.
+	server.addJob(jobId.String(), job)
+	err = job.Start()
+	server.saveJob(jobId.String(), job)
+	go func() {
+		job.Wait()
+		if server.Bridge != nil {
+			server.Bridge.Detach(jobId.String())
+		}
+		server.saveJob(jobId.String(), job)
+		server.reapCgroup(jobId.String(), job)
+	}()
+	if err != nil {
+		log.Infof("Unable to run job %s: %s", jobId, err)
+		return nil, fmt.Errorf("job %s failed to start: %s", jobId, err)
+	}
 
 	resp := StartJobResponse{
 		JobId: jobId.String(),
 	}
 
-	server.Jobs[jobId.String()] = job
-
 	log.Debugf("User (%s) has started job %s.", user, jobId)
 	return &resp, nil
 }
 
 func (server *ApiServer) Stop(ctx context.Context, input *StopJobInput) (*StopJobResponse, error) {
 	log.Infof("Stopping job (%s)", input.JobId)
-	if !server.AuthorizeJob(ctx, input.JobId) {
+	job, ok := server.AuthorizeJob(ctx, input.JobId)
+	if !ok {
 		return nil, fmt.Errorf("no job found with id: %s", input.JobId)
 	}
-	job := server.Jobs[input.JobId]
 
-	if !job.IsRunning() {
-		err := job.Kill()
+	sig := syscall.SIGTERM
+	if input.Signal != "" {
+		var err error
+		if sig, err = command.ParseSignal(input.Signal); err != nil {
+			return nil, err
+		}
+	}
+	gracePeriod := defaultGracePeriod
+	if input.GracePeriod != nil {
+		if *input.GracePeriod < 0 {
+			return nil, fmt.Errorf("grace period cannot be negative, got %d", *input.GracePeriod)
+		}
+		gracePeriod = time.Duration(*input.GracePeriod) * time.Second
+	}
+
+	if job.IsRunning() {
+		err := job.Stop(sig, gracePeriod)
 		if err != nil {
 			log.Errorf("Error stopping job (%s): %s", input.JobId, err)
 			return nil, err
 		}
 	}
 
-	for i := 0; i < 5 && job.Status == command.JobStatusRunning; i++ {
-		job.UpdateStatus()
-		time.Sleep(1 * time.Second)
+	select {
+	case <-job.Done():
+	case <-time.After(stopTimeout):
 	}
 
-	if job.Status == command.JobStatusRunning {
+	state := job.State()
+	if state.Status == command.JobStatusRunning {
 		return nil, fmt.Errorf("job %s is still running", input.JobId)
 	}
 
 	response := &StopJobResponse{
-		ExitCode: int32(job.ExitCode),
+		ExitCode:   int32(state.ExitCode),
+		Status:     state.Status,
+		Signal:     state.Signal,
+		StartTime:  timestamp(state.StartTime),
+		EndTime:    timestamp(state.EndTime),
+		StartError: state.StartError,
 	}
 
 	return response, nil
@@ -86,52 +225,455 @@ func (server *ApiServer) Stop(ctx context.Context, input *StopJobInput) (*StopJo
 
 func (server *ApiServer) Status(ctx context.Context, input *StatusInput) (*StatusResponse, error) {
 	log.Infof("Getting status of job (%s)", input.JobId)
-	if !server.AuthorizeJob(ctx, input.JobId) {
+	job, ok := server.AuthorizeJob(ctx, input.JobId)
+	if !ok {
 		return nil, fmt.Errorf("no job found with id: %s", input.JobId)
 	}
-	job := server.Jobs[input.JobId]
-	job.UpdateStatus()
-	log.Infof("Job %s: %+v", input.JobId, job)
+	if err := job.UpdateUsage(); err != nil {
+		log.Warnf("Unable to read usage of job (%s): %s", input.JobId, err)
+	}
+	state := job.State()
+	log.Infof("Job %s: %+v", input.JobId, state)
 	response := &StatusResponse{
-		Status:   job.Status,
-		ExitCode: int32(job.ExitCode),
+		Status:     state.Status,
+		ExitCode:   int32(state.ExitCode),
+		Signal:     state.Signal,
+		StartTime:  timestamp(state.StartTime),
+		EndTime:    timestamp(state.EndTime),
+		StartError: state.StartError,
+		Usage:      jobUsage(state.Usage),
+	}
+	return response, nil
+}
+
+// Usage returns the resources used by a job. Usage is read from the job's
+// cgroup while it exists, and the final usage is returned afterwards.
+func (server *ApiServer) Usage(ctx context.Context, input *UsageInput) (*UsageResponse, error) {
+	log.Infof("Getting usage of job (%s)", input.JobId)
+	job, ok := server.AuthorizeJob(ctx, input.JobId)
+	if !ok {
+		return nil, fmt.Errorf("no job found with id: %s", input.JobId)
+	}
+	if err := job.UpdateUsage(); err != nil {
+		return nil, fmt.Errorf("unable to read usage of job %s: %s", input.JobId, err)
+	}
+	return &UsageResponse{Usage: jobUsage(job.State().Usage)}, nil
+}
+
+// UpdateLimits changes the resource limits of a running job.
+func (server *ApiServer) UpdateLimits(ctx context.Context, input *UpdateLimitsInput) (*UpdateLimitsResponse, error) {
+	log.Infof("Updating limits of job (%s)", input.JobId)
+	job, ok := server.AuthorizeJob(ctx, input.JobId)
+	if !ok {
+		return nil, fmt.Errorf("no job found with id: %s", input.JobId)
+	}
+	if !job.IsRunning() {
+		return nil, fmt.Errorf("job %s is not running", input.JobId)
+	}
+
+	update := cgroup.LimitUpdate{
+		Cpu:        optionalInt(input.Cpu),
+		Memory:     optionalInt(input.Memory),
+		MemoryHigh: optionalInt(input.MemoryHigh),
+		Io:         optionalInt(input.Blkio),
+	}
+	if server.Controllers != nil {
+		if unsupported := server.Controllers.UnsupportedUpdate(update); len(unsupported) != 0 {
+			return nil, fmt.Errorf("limits not supported on this host: %s", strings.Join(unsupported, ", "))
+		}
+	}
+	if err := job.UpdateLimits(update); err != nil {
+		log.Errorf("Error updating limits of job (%s): %s", input.JobId, err)
+		return nil, fmt.Errorf("unable to update limits of job %s: %s", input.JobId, err)
+	}
+
+	limits := job.Limits()
+	response := &UpdateLimitsResponse{
+		Cpu:        int32(limits.Cpu),
+		Memory:     int32(limits.Memory),
+		MemoryHigh: int32(limits.MemoryHigh),
+		Blkio:      int32(limits.Io),
 	}
 	return response, nil
 }
 
 func (server *ApiServer) Monitor(input *MonitorJobInput, stream Jobs_MonitorServer) error {
 	log.Infof("Starting monitoring on job (%s)", input.JobId)
-	if !server.AuthorizeJob(stream.Context(), input.JobId) {
+	job, ok := server.AuthorizeJob(stream.Context(), input.JobId)
+	if !ok {
+		return fmt.Errorf("no job found with id: %s", input.JobId)
+	}
+	options := streamOptions{
+		offsets:   [2]int64{int64(input.Offset), int64(input.StderrOffset)},
+		tailLines: int(input.TailLines),
+		follow:    input.Follow == nil || *input.Follow,
+	}
+	if input.Records {
+		if job.Records == nil {
+			return fmt.Errorf("job (%s) was not started with timestamps", input.JobId)
+		}
+		return streamRecords(job.Records, stream, options)
+	}
+	return streamOutput(job, stream, options)
+}
+
+// Attach forwards stdin received from the client to a running job, and streams
+// the job's output back in the same way as Monitor.
+func (server *ApiServer) Attach(stream Jobs_AttachServer) error {
+	input, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	log.Infof("Attaching to job (%s)", input.JobId)
+	job, ok := server.AuthorizeJob(stream.Context(), input.JobId)
+	if !ok {
 		return fmt.Errorf("no job found with id: %s", input.JobId)
 	}
-	job := server.Jobs[input.JobId]
 
-	bytesCurrent := 0
-	for job.Status == command.JobStatusRunning || job.Output.HasNew(bytesCurrent) {
-		if job.Output.HasNew(bytesCurrent) {
-			var newData string
-			newData, bytesCurrent = job.Output.Read(bytesCurrent)
-			chunk := &MonitorJobResponse{
-				Chunk: newData,
+	go func() {
+		for {
+			if size := input.WindowSize; size != nil {
+				if err := job.Resize(uint16(size.Rows), uint16(size.Cols)); err != nil {
+					log.Errorf("Error resizing terminal of job (%s): %s", job.CgroupName, err)
+				}
+			}
+			if err := job.WriteInput(input.Stdin, input.CloseStdin); err != nil {
+				log.Errorf("Error forwarding input to job (%s): %s", job.CgroupName, err)
+				return
 			}
-			stream.Send(chunk)
+			if input, err = stream.Recv(); err != nil {
+				return
+			}
+		}
+	}()
+
+	return streamOutput(job, stream, streamOptions{follow: true})
+}
+
+// namespaces returns the namespaces selected for a job, if the client is
+// allowed to use them. Jobs sharing the network of the host are not put in a
+// net namespace, and the other network modes require one.
+func (server *ApiServer) namespaces(user string, selected *Namespaces, mode string) ([]string, error) {
+	namespaces := command.DefaultNamespaces
+	if selected != nil {
+		namespaces = append([]string{}, selected.Names...)
+	}
+	if err := command.ValidateNamespaces(namespaces); err != nil {
+		return nil, err
+	}
+	if mode == network.ModeHost {
+		shared := []string{}
+		for _, ns := range namespaces {
+			if ns != command.NamespaceNet {
+				shared = append(shared, ns)
+			}
+		}
+		namespaces = shared
+	} else if mode != "" && !command.HasNamespace(namespaces, command.NamespaceNet) {
+		return nil, fmt.Errorf("network mode %s requires the %s namespace", mode, command.NamespaceNet)
+	}
+	if err := server.Policy.For(user).CheckNamespaces(namespaces); err != nil {
+		return nil, err
+	}
+	return namespaces, nil
+}
+
+// rootfs returns the root filesystem of a job, if the client is allowed to use
+// the host paths it needs. Paths are resolved so that symlinks cannot lead
+// outside of the allowed paths. Jobs without layers use the host's filesystem.
+func (server *ApiServer) rootfs(user string, layers []string, mounts []*Mount, namespaces []string) (*rootfs.Config, error) {
+	if len(layers) == 0 {
+		if len(mounts) > 0 {
+			return nil, fmt.Errorf("mounts require a root filesystem")
+		}
+		return nil, nil
+	}
+	if !command.HasNamespace(namespaces, command.NamespaceMount) {
+		return nil, fmt.Errorf("a root filesystem requires the %s namespace", command.NamespaceMount)
+	}
+
+	rules := server.Policy.For(user)
+	resolve := func(path string) (string, error) {
+		if !filepath.IsAbs(path) {
+			return "", fmt.Errorf("path is not absolute: %s", path)
+		}
+		resolved, err := filepath.EvalSymlinks(path)
+		if err != nil {
+			return "", err
+		}
+		return resolved, rules.CheckPath(resolved)
+	}
+
+	cfg := &rootfs.Config{}
+	for _, layer := range layers {
+		resolved, err := resolve(layer)
+		if err != nil {
+			return nil, err
 		}
-		job.UpdateStatus()
-		time.Sleep(1 * time.Second)
+		if info, err := os.Stat(resolved); err != nil || !info.IsDir() {
+			return nil, fmt.Errorf("root filesystem layer is not a directory: %s", layer)
+		}
+		cfg.Layers = append(cfg.Layers, resolved)
+	}
+	for _, m := range mounts {
+		mount := rootfs.Mount{Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly}
+		if err := mount.Validate(); err != nil {
+			return nil, err
+		}
+		resolved, err := resolve(mount.Source)
+		if err != nil {
+			return nil, err
+		}
+		mount.Source = resolved
+		cfg.Mounts = append(cfg.Mounts, mount)
+	}
+	return cfg, nil
+}
+
+// profile loads the security profile of a job, if the client may use it. Jobs
+// without a profile are not restricted.
+func (server *ApiServer) profile(user, name string) (*security.Profile, error) {
+	name, err := server.Policy.For(user).Profile(name)
+	if err != nil || name == "" {
+		return nil, err
+	}
+	path, err := security.ProfilePath(server.ProfileDir, name)
+	if err != nil {
+		return nil, err
+	}
+	return security.Load(path)
+}
+
+// checkNetwork returns the port forwards of a job, if its network mode is
+// available.
+func (server *ApiServer) checkNetwork(mode string, forwards []*PortForward) ([]network.PortForward, error) {
+	if mode != "" {
+		if err := network.ValidateMode(mode); err != nil {
+			return nil, err
+		}
+	}
+	if mode == network.ModeBridge && server.Bridge == nil {
+		return nil, fmt.Errorf("bridge networking is unavailable on this host")
+	}
+	if len(forwards) > 0 && mode != network.ModeBridge {
+		return nil, fmt.Errorf("ports can only be forwarded with the %s network mode", network.ModeBridge)
+	}
+	if len(forwards) > 0 && !server.Bridge.CanForwardPorts() {
+		return nil, fmt.Errorf("ports cannot be forwarded on this host")
+	}
+
+	ports := []network.PortForward{}
+	for _, forward := range forwards {
+		if forward.HostPort > 65535 || forward.JobPort > 65535 {
+			return nil, fmt.Errorf("ports must be between 1 and 65535")
+		}
+		port := network.PortForward{
+			HostPort: uint16(forward.HostPort),
+			JobPort:  uint16(forward.JobPort),
+			Protocol: forward.Protocol,
+		}
+		if port.Protocol == "" {
+			port.Protocol = network.ProtocolTcp
+		}
+		if err := port.Validate(); err != nil {
+			return nil, err
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+// checkLimits refuses limits that need cgroup controllers which are not
+// available. The limits are removed instead when DegradeLimits is set.
+func (server *ApiServer) checkLimits(cg *cgroup.CgroupConfig) error {
+	if server.Controllers == nil {
+		return nil
+	}
+	unsupported := server.Controllers.Unsupported(cg)
+	if len(unsupported) == 0 {
+		return nil
+	}
+	if !server.DegradeLimits {
+		return fmt.Errorf("limits not supported on this host: %s", strings.Join(unsupported, ", "))
 	}
+	server.Controllers.Degrade(cg)
+	log.Warnf("Starting job (%s) without unsupported limits: %s", cg.Name, strings.Join(unsupported, ", "))
 	return nil
 }
 
-func (server *ApiServer) AuthorizeJob(ctx context.Context, jobId string) bool {
+// outputSender is implemented by the server side of streams returning job
+// output.
+type outputSender interface {
+	Send(*MonitorJobResponse) error
+	Context() context.Context
+}
+
+// streamOptions select the output sent by streamOutput.
+type streamOptions struct {
+	// offsets are where stdout and stderr start.
+	offsets [2]int64
+	// tailLines starts each stream at its last lines instead, when set, but
+	// not before its offset.
+	tailLines int
+	// follow sends output as soon as it is written, until the job has ended.
+	// Otherwise only the output written so far is sent.
+	follow bool
+}
+
+// start returns where the output of buf starts, from offset on.
+func (options streamOptions) start(buf *command.OutputBuffer, offset int64) int64 {
+	if options.tailLines > 0 {
+		if tail := buf.TailOffset(options.tailLines); tail > offset {
+			return tail
+		}
+	}
+	return offset
+}
+
+// end returns where the output of buf ends, or -1 when it is followed.
+func (options streamOptions) end(buf *command.OutputBuffer) int64 {
+	if options.follow {
+		return -1
+	}
+	return buf.Size()
+}
+
+// streamOutput sends the job's output to the stream.
+func streamOutput(job *command.JobConfig, stream outputSender, options streamOptions) error {
+	// Streams do not support concurrent sends.
+	var sendLock sync.Mutex
+	errs := make(chan error, 2)
+	for i, output := range []struct {
+		buf    *command.OutputBuffer
+		stream OutputStream
+	}{{job.Output, OutputStream_STDOUT}, {job.ErrOutput, OutputStream_STDERR}} {
+		outputStream := output.stream
+		send := func(data []byte, offset, skipped int64) error {
+			sendLock.Lock()
+			defer sendLock.Unlock()
+			return stream.Send(&MonitorJobResponse{
+				Chunk:      data,
+				Stream:     outputStream,
+				Skipped:    uint64(skipped),
+				Offset:     uint64(offset),
+				NextOffset: uint64(offset + int64(len(data))),
+			})
+		}
+		start, end := options.start(output.buf, options.offsets[i]), options.end(output.buf)
+		go func(buf *command.OutputBuffer) {
+			errs <- sendOutput(stream.Context(), buf, start, end, send)
+		}(output.buf)
+	}
+
+	// Returning ends the stream, which stops the other sender as well.
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// streamRecords sends the records of a job to the stream. Records are only
+// sent once they have been read completely.
+func streamRecords(buf *command.OutputBuffer, stream outputSender, options streamOptions) error {
+	var pending []byte
+	var pendingOffset, pendingSkipped int64
+	send := func(data []byte, offset, skipped int64) error {
+		if skipped > 0 {
+			// The rest of a record cannot follow skipped output.
+			skipped += int64(len(pending))
+			pending = nil
+		}
+		if len(pending) == 0 {
+			pendingOffset = offset
+		}
+		pending = append(pending, data...)
+		pendingSkipped += skipped
+
+		end := bytes.LastIndexByte(pending, '\n') + 1
+		if end == 0 {
+			return nil
+		}
+		response := &MonitorJobResponse{
+			Skipped:    uint64(pendingSkipped),
+			Offset:     uint64(pendingOffset),
+			NextOffset: uint64(pendingOffset + int64(end)),
+		}
+		for _, record := range command.ParseRecords(pending[:end]) {
+			response.Records = append(response.Records, &OutputRecord{
+				Sequence: record.Sequence,
+				Time:     timestamppb.New(record.Time),
+				Stream:   recordStream(record.Stream),
+				Line:     record.Line,
+			})
+		}
+		pending = append([]byte{}, pending[end:]...)
+		pendingOffset += int64(end)
+		pendingSkipped = 0
+		return stream.Send(response)
+	}
+	start, end := options.start(buf, options.offsets[0]), options.end(buf)
+	return sendOutput(stream.Context(), buf, start, end, send)
+}
+
+// recordStream returns the OutputStream of a record.
+func recordStream(stream string) OutputStream {
+	if stream == command.StreamStderr {
+		return OutputStream_STDERR
+	}
+	return OutputStream_STDOUT
+}
+
+// sendOutput sends the output of buf from offset on. Output is sent as soon as
+// it is written, until the job has ended, unless end is set. Then only the
+// output up to end is sent.
+func sendOutput(ctx context.Context, buf *command.OutputBuffer, offset, end int64, send func(data []byte, offset, skipped int64) error) error {
+	for {
+		var data []byte
+		var next, skipped int64
+		var err error
+		if end < 0 {
+			data, next, skipped, err = buf.Next(ctx, offset)
+		} else if offset < end {
+			data, next, skipped = buf.Read(offset)
+		} else {
+			return nil
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := send(data, next-int64(len(data)), skipped); err != nil {
+			return err
+		}
+		offset = next
+	}
+}
+
+// AuthorizeJob returns the job if it is owned by the user making the request.
+func (server *ApiServer) AuthorizeJob(ctx context.Context, jobId string) (*command.JobConfig, bool) {
 	user := getUserFromContext(ctx)
 
+	server.jobsLock.RLock()
 	job, ok := server.Jobs[jobId]
+	server.jobsLock.RUnlock()
 	if ok && job.Owner == user {
-		return true
+		return job, true
 	}
 
 	log.Infof("User (%s) is not authorized to interact with job (%s)", user, jobId)
-	return false
+	return nil, false
+}
+
+// addJob adds a job to the jobs known by the server.
+func (server *ApiServer) addJob(jobId string, job *command.JobConfig) {
+	server.jobsLock.Lock()
+	defer server.jobsLock.Unlock()
+	server.Jobs[jobId] = job
 }
 
 func getUserFromContext(ctx context.Context) string {
@@ -146,10 +688,19 @@ func getUserFromContext(ctx context.Context) string {
 func (server *ApiServer) List(ctx context.Context, _ *Empty) (*ListJobsResponse, error) {
 	user := getUserFromContext(ctx)
 	ownedJobs := []*JobInfo{}
+	server.jobsLock.RLock()
+	defer server.jobsLock.RUnlock()
 	for id, job := range server.Jobs {
 		if job.Owner == user {
+			state := job.State()
 			newJob := &JobInfo{
-				TaskId: id,
+				TaskId:     id,
+				Status:     state.Status,
+				ExitCode:   int32(state.ExitCode),
+				Signal:     state.Signal,
+				StartTime:  timestamp(state.StartTime),
+				EndTime:    timestamp(state.EndTime),
+				StartError: state.StartError,
 			}
 			ownedJobs = append(ownedJobs, newJob)
 		}
@@ -159,3 +710,50 @@ func (server *ApiServer) List(ctx context.Context, _ *Empty) (*ListJobsResponse,
 	}
 	return resp, nil
 }
+
+// timestamp converts t to a protobuf timestamp. Zero times are left unset.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// jobUsage converts the resource usage of a cgroup to its protobuf message.
+func jobUsage(usage cgroup.Usage) *JobUsage {
+	return &JobUsage{
+		CpuUsec:       usage.CpuUsec,
+		UserUsec:      usage.UserUsec,
+		SystemUsec:    usage.SystemUsec,
+		MemoryCurrent: usage.MemoryCurrent,
+		MemoryPeak:    usage.MemoryPeak,
+		OomKills:      usage.OomKills,
+		ReadBytes:     usage.ReadBytes,
+		WriteBytes:    usage.WriteBytes,
+	}
+}
+
+// optionalInt converts an optional protobuf field to an optional int.
+func optionalInt(value *int32) *int {
+	if value == nil {
+		return nil
+	}
+	converted := int(*value)
+	return &converted
+}
+
+// ioMax converts the io limits of a request to those of a cgroup.
+func ioMax(limits []*IoMax) []cgroup.IoMax {
+	converted := []cgroup.IoMax{}
+	for _, limit := range limits {
+		converted = append(converted, cgroup.IoMax{
+			Major: limit.Major,
+			Minor: limit.Minor,
+			Rbps:  limit.Rbps,
+			Wbps:  limit.Wbps,
+			Riops: limit.Riops,
+			Wiops: limit.Wiops,
+		})
+	}
+	return converted
+}
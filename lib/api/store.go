@@ -0,0 +1,154 @@
+package api
+
+import (
+	"github.com/bill-rich/rjob/lib/command"
+	"github.com/bill-rich/rjob/lib/store"
+	log "github.com/sirupsen/logrus"
+)
+
+// RestoreJobs loads all jobs saved in the store. Jobs that had not ended when
+// the server stopped can no longer be tracked, so they are marked as lost.
+func (server *ApiServer) RestoreJobs() error {
+	if server.Store == nil {
+		return nil
+	}
+
+	records, err := server.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		if record.Status == command.JobStatusPending || record.Status == command.JobStatusRunning {
+			record.Status = command.JobStatusLost
+			if err := server.Store.Save(record); err != nil {
+				log.Errorf("Unable to mark job (%s) as lost: %s", record.Id, err)
+			}
+		}
+
+		job := &command.JobConfig{
+			Command:    record.Command,
+			Args:       record.Args,
+			Owner:      record.Owner,
+			Tty:        record.Tty,
+			CgroupName: record.Id,
+			Output:     server.restoreOutput(record, store.Stdout),
+			ErrOutput:  server.restoreOutput(record, store.Stderr),
+			JobState: command.JobState{
+				Status:     record.Status,
+				ExitCode:   record.ExitCode,
+				Signal:     record.Signal,
+				StartTime:  record.StartTime,
+				EndTime:    record.EndTime,
+				StartError: record.StartError,
+				Usage:      record.Usage,
+			},
+		}
+		if record.Timestamps {
+			job.Records = server.restoreOutput(record, store.Records)
+		}
+		server.addJob(record.Id, job)
+	}
+
+	log.Infof("Restored %d jobs from the job store", len(records))
+	return nil
+}
+
+// restoreOutput returns an OutputBuffer holding the saved output of a stream.
+// The output is read back from the store as needed. Restored jobs have ended,
+// so no more output is written to the buffer.
+func (server *ApiServer) restoreOutput(record store.JobRecord, stream store.Stream) *command.OutputBuffer {
+	buf, err := server.outputBuffer(record.Id, stream, record.OutputStart[stream])
+	if err != nil {
+		log.Errorf("Unable to restore %s of job (%s): %s", stream, record.Id, err)
+		buf = &command.OutputBuffer{}
+	}
+	buf.Close()
+	return buf
+}
+
+// outputBuffers sets up the output buffers of a new job. The output is also
+// recorded as timestamped lines when timestamps is set. The job is saved
+// whenever old output is discarded, so that the saved output can be restored
+// at the right offsets.
+func (server *ApiServer) outputBuffers(jobId string, job *command.JobConfig, timestamps bool) error {
+	var err error
+	if job.Output, err = server.outputBuffer(jobId, store.Stdout, 0); err != nil {
+		return err
+	}
+	if job.ErrOutput, err = server.outputBuffer(jobId, store.Stderr, 0); err != nil {
+		return err
+	}
+	if timestamps {
+		if job.Records, err = server.outputBuffer(jobId, store.Records, 0); err != nil {
+			return err
+		}
+	}
+
+	for _, buf := range []*command.OutputBuffer{job.Output, job.ErrOutput, job.Records} {
+		if buf != nil {
+			buf.AfterDiscard = func() {
+				server.saveJob(jobId, job)
+			}
+		}
+	}
+	return nil
+}
+
+// outputBuffer returns an OutputBuffer for a stream of a job. Output is saved in
+// the store, and only the most recent output is kept in memory. The saved
+// output starts at offset start of the stream.
+func (server *ApiServer) outputBuffer(jobId string, stream store.Stream, start int64) (*command.OutputBuffer, error) {
+	if server.Store == nil {
+		return &command.OutputBuffer{Limits: server.OutputLimits}, nil
+	}
+	outputLog, err := server.Store.OpenOutput(jobId, stream)
+	if err != nil {
+		return nil, err
+	}
+	return command.NewOutputBuffer(server.OutputLimits, outputLog, start)
+}
+
+// saveJob saves the current state of a job in the store. Jobs that were
+// removed are not saved, since the job's goroutines may outlive it.
+func (server *ApiServer) saveJob(jobId string, job *command.JobConfig) {
+	if server.Store == nil {
+		return
+	}
+	server.storeLock.Lock()
+	defer server.storeLock.Unlock()
+
+	server.jobsLock.RLock()
+	current, ok := server.Jobs[jobId]
+	server.jobsLock.RUnlock()
+	if !ok || current != job {
+		return
+	}
+
+	state := job.State()
+	record := store.JobRecord{
+		Id:         jobId,
+		Owner:      job.Owner,
+		Command:    job.Command,
+		Args:       job.Args,
+		Tty:        job.Tty,
+		Timestamps: job.Records != nil,
+		Status:     state.Status,
+		ExitCode:   state.ExitCode,
+		Signal:     state.Signal,
+		StartTime:  state.StartTime,
+		EndTime:    state.EndTime,
+		StartError: state.StartError,
+		Usage:      state.Usage,
+		OutputStart: map[store.Stream]int64{
+			store.Stdout: job.Output.LogStart(),
+			store.Stderr: job.ErrOutput.LogStart(),
+		},
+	}
+	if job.Records != nil {
+		record.OutputStart[store.Records] = job.Records.LogStart()
+	}
+	if err := server.Store.Save(record); err != nil {
+		log.Errorf("Unable to save job (%s): %s", jobId, err)
+	}
+}
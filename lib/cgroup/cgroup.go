@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
@@ -11,11 +14,30 @@ import (
 
 // Set a few constants for simplicity. These could be read from a configuration.
 const (
+	// CgroupMountDir is where the private cgroup hierarchy is mounted by
+	// default.
 	CgroupMountDir = "/tmp/rjob/cgroup"
 	fileMode       = 0660
+	// dirMode allows cgroups to be used by an unprivileged owner.
+	dirMode        = 0755
 	cpuMaxFile     = "cpu.max"
 	memoryMaxFile  = "memory.max"
+	memoryHighFile = "memory.high"
+	memorySwapFile = "memory.swap.max"
+	pidsMaxFile    = "pids.max"
+	cpusetCpusFile = "cpuset.cpus"
+	cpusetMemsFile = "cpuset.mems"
 	ioWeightFile   = "io.weight"
+	ioMaxFile      = "io.max"
+	procsFile      = "cgroup.procs"
+	killFile       = "cgroup.kill"
+	freezeFile     = "cgroup.freeze"
+	eventsFile     = "cgroup.events"
+
+	// emptyTimeout is how long Delete waits for the processes of a cgroup
+	// to exit.
+	emptyTimeout      = 5 * time.Second
+	emptyPollInterval = 100 * time.Millisecond
 )
 
 // CgroupConfig is used to create, delete, and manage a cgroup.
@@ -24,11 +46,39 @@ type CgroupConfig struct {
 
 	Cpu    int
 	Memory int
-	Io     int
+	// MemoryHigh is the memory usage above which the processes of the
+	// cgroup are throttled and put under heavy reclaim pressure.
+	MemoryHigh int
+	// MemorySwap is the maximum swap usage. Like Memory, it is in KB and 0
+	// means no limit.
+	MemorySwap int
+	Io         int
+	// Pids is the maximum number of processes. 0 means no limit.
+	Pids int
+
+	// CpusetCpus and CpusetMems pin the cgroup to CPUs and memory nodes, as
+	// lists such as "0-3,6". They are left unset when empty.
+	CpusetCpus string
+	CpusetMems string
+
+	// IoMax caps the bandwidth and IOPS on individual block devices.
+	IoMax []IoMax
 
 	Path string
 }
 
+// IoMax limits the bandwidth in bytes per second, and the IOPS, of a cgroup on
+// the block device with the given major and minor numbers. Limits of 0 mean no
+// limit.
+type IoMax struct {
+	Major uint32
+	Minor uint32
+	Rbps  uint64
+	Wbps  uint64
+	Riops uint64
+	Wiops uint64
+}
+
 // Mount will mount the cgroup hierarchy at mountDir.
 func Mount(mountDir string) error {
 	log.Debugf("Mounting cgroup hierarchy at %s", mountDir)
@@ -43,7 +93,7 @@ func Mount(mountDir string) error {
 	}
 
 	log.Debugf("Cgroup hierarchy mounted successfully")
-	return verifyCgroupRequirements()
+	return nil
 }
 
 // Umount unmounts the cgroup hierarchy mounted at mountDir.
@@ -58,40 +108,162 @@ func Umount(mountDir string) error {
 	return nil
 }
 
-func verifyCgroupRequirements() error {
-	// TODO: Check that required subsystems are enabled, control files exist, etc.
-	return nil
-}
-
 // Create will create the configured cgroup and set the limits for cpu, memory,
-// and io.
+// and io. Limits left at their defaults are not written, so cgroups without
+// limits can be created on hosts missing some controllers. If any limit cannot
+// be set, the cgroup is removed again, so that no job runs without its limits.
 func (cg *CgroupConfig) Create() error {
 	log.Debugf("Creating cgroup %s", cg.Name)
 
-	if err := os.Mkdir(cg.Path, fileMode); err != nil {
+	if err := os.Mkdir(cg.Path, dirMode); err != nil {
 		return fmt.Errorf("error making cgroup directory %s: %s", cg.Path, err)
 	}
-
-	// Set cgroup limits
-	if err := cg.setCpuLimit(); err != nil {
+	if err := cg.setLimits(); err != nil {
+		if removeErr := os.Remove(cg.Path); removeErr != nil {
+			log.Errorf("Unable to remove cgroup %s: %s", cg.Name, removeErr)
+		}
 		return err
 	}
-	if err := cg.setMemoryLimit(); err != nil {
-		return err
+
+	log.Debugf("Cgroup %s created successfully", cg.Name)
+	return nil
+}
+
+// setLimits writes every limit of a new cgroup that is not left at its
+// default.
+func (cg *CgroupConfig) setLimits() error {
+	if cg.Cpu != 100 {
+		if err := cg.setCpuLimit(); err != nil {
+			return err
+		}
 	}
-	if err := cg.setBlkIoLimit(); err != nil {
-		return err
+	if cg.Memory != 0 {
+		if err := cg.setMemoryLimit(); err != nil {
+			return err
+		}
+	}
+	if cg.MemoryHigh != 0 {
+		if err := cg.setMemoryHighLimit(); err != nil {
+			return err
+		}
 	}
+	if cg.Io != 100 {
+		if err := cg.setBlkIoLimit(); err != nil {
+			return err
+		}
+	}
+	return cg.setOptionalLimits()
+}
 
-	log.Debugf("Cgroup %s created successfully", cg.Name)
+// Validate checks all limits of the cgroup, without creating it.
+func (cg *CgroupConfig) Validate() error {
+	validators := []func() (string, error){
+		cg.getCpuMax, cg.getMemMax, cg.getMemHigh, cg.getMemSwapMax, cg.getIoWeight, cg.getPidsMax,
+	}
+	for _, validate := range validators {
+		if _, err := validate(); err != nil {
+			return err
+		}
+	}
+	for _, cpuset := range []string{cg.CpusetCpus, cg.CpusetMems} {
+		if cpuset == "" {
+			continue
+		}
+		if _, err := getCpuset(cpuset); err != nil {
+			return err
+		}
+	}
+	_, err := cg.getIoMax()
+	return err
+}
+
+// LimitUpdate holds new limits for a cgroup. Limits that are nil are left
+// unchanged.
+type LimitUpdate struct {
+	Cpu        *int
+	Memory     *int
+	MemoryHigh *int
+	Io         *int
+}
+
+// UpdateLimits changes the limits of an existing cgroup. All new limits are
+// validated before any are written, so an invalid update leaves the cgroup
+// unchanged. Limits are then written one at a time, in a fixed order, and cg
+// is updated along with each limit written. If the kernel refuses a limit, such
+// as a memory limit below the current usage, the limits written before it stay
+// in effect and are named in the error.
+func (cg *CgroupConfig) UpdateLimits(update LimitUpdate) error {
+	log.Debugf("Updating limits of cgroup %s", cg.Name)
+
+	updated := *cg
+	writes := []limitWrite{}
+	if update.Cpu != nil {
+		updated.Cpu = *update.Cpu
+		cpuMax, err := updated.getCpuMax()
+		if err != nil {
+			return err
+		}
+		writes = append(writes, limitWrite{"cpu", cpuMaxFile, cpuMax, func(cg *CgroupConfig) { cg.Cpu = updated.Cpu }})
+	}
+	if update.Memory != nil {
+		updated.Memory = *update.Memory
+		memMax, err := updated.getMemMax()
+		if err != nil {
+			return err
+		}
+		writes = append(writes, limitWrite{"memory", memoryMaxFile, memMax, func(cg *CgroupConfig) { cg.Memory = updated.Memory }})
+	}
+	if update.MemoryHigh != nil {
+		updated.MemoryHigh = *update.MemoryHigh
+		memHigh, err := updated.getMemHigh()
+		if err != nil {
+			return err
+		}
+		writes = append(writes, limitWrite{"memory high", memoryHighFile, memHigh, func(cg *CgroupConfig) { cg.MemoryHigh = updated.MemoryHigh }})
+	}
+	if update.Io != nil {
+		updated.Io = *update.Io
+		ioWeight, err := updated.getIoWeight()
+		if err != nil {
+			return err
+		}
+		writes = append(writes, limitWrite{"io", ioWeightFile, ioWeight, func(cg *CgroupConfig) { cg.Io = updated.Io }})
+	}
+
+	applied := []string{}
+	for _, write := range writes {
+		if err := setCgroupLimit(filepath.Join(cg.Path, write.file), write.value); err != nil {
+			if len(applied) == 0 {
+				return err
+			}
+			return fmt.Errorf("%s (limits updated before the error: %s)", err, strings.Join(applied, ", "))
+		}
+		write.apply(cg)
+		applied = append(applied, write.limit)
+	}
+
+	log.Debugf("Limits of cgroup %s updated successfully", cg.Name)
 	return nil
 }
 
+// limitWrite is a limit written to a file of a cgroup by UpdateLimits. apply
+// records the new limit in the CgroupConfig once it was written.
+type limitWrite struct {
+	limit string
+	file  string
+	value string
+	apply func(cg *CgroupConfig)
+}
+
 // Delete will delete the configured cgroup by removing the directory. Processes
-// int the cgroup must be killed or moved before deleting.
+// in the cgroup must be killed or moved before deleting. The cgroup is only
+// removed once all of its processes have exited.
 func (cg *CgroupConfig) Delete() error {
 	log.Debugf("Deleting cgroup %s", cg.Name)
 
+	if err := cg.WaitEmpty(emptyTimeout); err != nil {
+		return fmt.Errorf("unable to remove cgroup %s: %s", cg.Name, err)
+	}
 	if err := os.Remove(cg.Path); err != nil {
 		return fmt.Errorf("unable to remove cgroup %s: %s", cg.Name, err)
 	}
@@ -100,6 +272,141 @@ func (cg *CgroupConfig) Delete() error {
 	return nil
 }
 
+// Procs returns the PIDs of all processes in the cgroup.
+func (cg *CgroupConfig) Procs() ([]int, error) {
+	procsPath := filepath.Join(cg.Path, procsFile)
+	data, err := os.ReadFile(procsPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", procsPath, err)
+	}
+	return parseProcs(data)
+}
+
+// Signal sends sig to every process in the cgroup. Processes that exit before
+// they are signalled are ignored.
+func (cg *CgroupConfig) Signal(sig unix.Signal) error {
+	pids, err := cg.Procs()
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("Sending %s to %d processes in cgroup %s", unix.SignalName(sig), len(pids), cg.Name)
+	for _, pid := range pids {
+		if err := unix.Kill(pid, sig); err != nil && err != unix.ESRCH {
+			return fmt.Errorf("error sending %s to %d: %s", unix.SignalName(sig), pid, err)
+		}
+	}
+	return nil
+}
+
+// Kill kills every process in the cgroup. Processes cannot escape by forking
+// while they are being killed.
+func (cg *CgroupConfig) Kill() error {
+	killPath := filepath.Join(cg.Path, killFile)
+	if _, err := os.Stat(killPath); err != nil {
+		// cgroup.kill is only available from Linux 5.14.
+		log.Debugf("Unable to use %s, freezing cgroup %s instead: %s", killFile, cg.Name, err)
+		return cg.killFrozen()
+	}
+
+	log.Debugf("Killing all processes in cgroup %s", cg.Name)
+	return setCgroupLimit(killPath, "1")
+}
+
+// killFrozen kills every process in the cgroup by freezing the cgroup before
+// sending SIGKILL to each process. Frozen processes still receive SIGKILL.
+func (cg *CgroupConfig) killFrozen() error {
+	freezePath := filepath.Join(cg.Path, freezeFile)
+	if err := setCgroupLimit(freezePath, "1"); err != nil {
+		return err
+	}
+	defer setCgroupLimit(freezePath, "0")
+
+	return cg.Signal(unix.SIGKILL)
+}
+
+// IsEmpty returns true if no processes are left in the cgroup or any of its
+// descendants.
+func (cg *CgroupConfig) IsEmpty() (bool, error) {
+	eventsPath := filepath.Join(cg.Path, eventsFile)
+	data, err := os.ReadFile(eventsPath)
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %s", eventsPath, err)
+	}
+	populated, err := parseKeyedValue(data, "populated")
+	if err != nil {
+		return false, fmt.Errorf("error parsing %s: %s", eventsPath, err)
+	}
+	return populated == 0, nil
+}
+
+// WaitEmpty waits until no processes are left in the cgroup.
+func (cg *CgroupConfig) WaitEmpty(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		empty, err := cg.IsEmpty()
+		if err != nil || empty {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("processes still running in cgroup %s after %s", cg.Name, timeout)
+		}
+		time.Sleep(emptyPollInterval)
+	}
+}
+
+// parseKeyedValue returns the value of key from the contents of a flat keyed
+// cgroup file, such as cgroup.events.
+func parseKeyedValue(data []byte, key string) (int64, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			return strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return 0, fmt.Errorf("key %s not found", key)
+}
+
+func parseProcs(data []byte) ([]int, error) {
+	pids := []int{}
+	for _, line := range strings.Fields(string(data)) {
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid PID %q in %s", line, procsFile)
+		}
+		pids = append(pids, pid)
+	}
+	return pids, nil
+}
+
+// RemoveOrphans kills the processes of, and deletes, every cgroup directly
+// under parentDir that isOrphan reports as orphaned.
+func RemoveOrphans(parentDir string, isOrphan func(name string) bool) error {
+	entries, err := os.ReadDir(parentDir)
+	if err != nil {
+		return fmt.Errorf("error reading cgroups in %s: %s", parentDir, err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() || !isOrphan(entry.Name()) {
+			continue
+		}
+		cg := CgroupConfig{
+			Name: entry.Name(),
+			Path: filepath.Join(parentDir, entry.Name()),
+		}
+		log.Infof("Removing orphaned cgroup %s", cg.Name)
+		if err := cg.Kill(); err != nil {
+			log.Errorf("Unable to kill processes of orphaned cgroup %s: %s", cg.Name, err)
+			continue
+		}
+		if err := cg.Delete(); err != nil {
+			log.Errorf("Unable to remove orphaned cgroup: %s", err)
+		}
+	}
+	return nil
+}
+
 func setCgroupLimit(path, limit string) error {
 	cgFile, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, fileMode)
 	if err != nil {
@@ -130,6 +437,14 @@ func (cg *CgroupConfig) setMemoryLimit() error {
 	return setCgroupLimit(filepath.Join(cg.Path, memoryMaxFile), memMax)
 }
 
+func (cg *CgroupConfig) setMemoryHighLimit() error {
+	memHigh, err := cg.getMemHigh()
+	if err != nil {
+		return err
+	}
+	return setCgroupLimit(filepath.Join(cg.Path, memoryHighFile), memHigh)
+}
+
 func (cg *CgroupConfig) setBlkIoLimit() error {
 	ioWeight, err := cg.getIoWeight()
 	if err != nil {
@@ -138,6 +453,57 @@ func (cg *CgroupConfig) setBlkIoLimit() error {
 	return setCgroupLimit(filepath.Join(cg.Path, ioWeightFile), ioWeight)
 }
 
+func (cg *CgroupConfig) setOptionalLimits() error {
+	if cg.MemorySwap != 0 {
+		memSwap, err := cg.getMemSwapMax()
+		if err != nil {
+			return err
+		}
+		if err := setCgroupLimit(filepath.Join(cg.Path, memorySwapFile), memSwap); err != nil {
+			return err
+		}
+	}
+	if cg.Pids != 0 {
+		pidsMax, err := cg.getPidsMax()
+		if err != nil {
+			return err
+		}
+		if err := setCgroupLimit(filepath.Join(cg.Path, pidsMaxFile), pidsMax); err != nil {
+			return err
+		}
+	}
+	if cg.CpusetCpus != "" {
+		cpus, err := getCpuset(cg.CpusetCpus)
+		if err != nil {
+			return err
+		}
+		if err := setCgroupLimit(filepath.Join(cg.Path, cpusetCpusFile), cpus); err != nil {
+			return err
+		}
+	}
+	if cg.CpusetMems != "" {
+		mems, err := getCpuset(cg.CpusetMems)
+		if err != nil {
+			return err
+		}
+		if err := setCgroupLimit(filepath.Join(cg.Path, cpusetMemsFile), mems); err != nil {
+			return err
+		}
+	}
+
+	ioMax, err := cg.getIoMax()
+	if err != nil {
+		return err
+	}
+	// io.max only accepts the limits of one device per write.
+	for _, limit := range ioMax {
+		if err := setCgroupLimit(filepath.Join(cg.Path, ioMaxFile), limit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (cg *CgroupConfig) getCpuMax() (string, error) {
 	switch {
 	case cg.Cpu < 1:
@@ -163,6 +529,16 @@ func (cg *CgroupConfig) getMemMax() (string, error) {
 	return fmt.Sprintf("%d", cg.Memory*1024), nil
 }
 
+func (cg *CgroupConfig) getMemHigh() (string, error) {
+	if cg.MemoryHigh < 1 {
+		if cg.MemoryHigh < 0 {
+			return "", fmt.Errorf("minimum memory high setting is 0 (no limit), got %d", cg.MemoryHigh)
+		}
+		return "max", nil
+	}
+	return fmt.Sprintf("%d", cg.MemoryHigh*1024), nil
+}
+
 func (cg *CgroupConfig) getIoWeight() (string, error) {
 	switch {
 	case cg.Io < 10:
@@ -172,3 +548,120 @@ func (cg *CgroupConfig) getIoWeight() (string, error) {
 	}
 	return fmt.Sprintf("default %d", cg.Io), nil
 }
+
+func (cg *CgroupConfig) getMemSwapMax() (string, error) {
+	if cg.MemorySwap < 1 {
+		if cg.MemorySwap < 0 {
+			return "", fmt.Errorf("minimum swap setting is 0 (no limit), got %d", cg.MemorySwap)
+		}
+		return "max", nil
+	}
+	return fmt.Sprintf("%d", cg.MemorySwap*1024), nil
+}
+
+func (cg *CgroupConfig) getPidsMax() (string, error) {
+	if cg.Pids < 1 {
+		if cg.Pids < 0 {
+			return "", fmt.Errorf("minimum pids setting is 0 (no limit), got %d", cg.Pids)
+		}
+		return "max", nil
+	}
+	return fmt.Sprintf("%d", cg.Pids), nil
+}
+
+// getCpuset validates a list of CPUs or memory nodes, such as "0-3,6".
+func getCpuset(list string) (string, error) {
+	for _, item := range strings.Split(list, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return "", fmt.Errorf("invalid cpuset %q: %q is not a number", list, bounds[0])
+		}
+		if len(bounds) == 2 {
+			last, err := strconv.ParseUint(bounds[1], 10, 32)
+			if err != nil {
+				return "", fmt.Errorf("invalid cpuset %q: %q is not a number", list, bounds[1])
+			}
+			if last < first {
+				return "", fmt.Errorf("invalid cpuset %q: range %s is reversed", list, item)
+			}
+		}
+	}
+	return list, nil
+}
+
+// getIoMax returns one line of io.max for each limited device.
+func (cg *CgroupConfig) getIoMax() ([]string, error) {
+	limits := []string{}
+	devices := map[string]bool{}
+	for _, limit := range cg.IoMax {
+		device := fmt.Sprintf("%d:%d", limit.Major, limit.Minor)
+		if devices[device] {
+			return nil, fmt.Errorf("io max set more than once for device %s", device)
+		}
+		devices[device] = true
+
+		limits = append(limits, fmt.Sprintf("%s rbps=%s wbps=%s riops=%s wiops=%s", device,
+			ioMaxValue(limit.Rbps), ioMaxValue(limit.Wbps), ioMaxValue(limit.Riops), ioMaxValue(limit.Wiops)))
+	}
+	return limits, nil
+}
+
+// ioMaxValue formats a limit of io.max, where 0 means no limit.
+func ioMaxValue(value uint64) string {
+	if value == 0 {
+		return "max"
+	}
+	return strconv.FormatUint(value, 10)
+}
+
+// ParseIoMax parses the limits of one device in the format of io.max, such as
+// "8:0 rbps=1048576 wiops=120". Limits that are not given, or are "max", are
+// not limited.
+func ParseIoMax(limit string) (IoMax, error) {
+	ioMax := IoMax{}
+	fields := strings.Fields(limit)
+	if len(fields) == 0 {
+		return ioMax, fmt.Errorf("invalid io max %q: no device given", limit)
+	}
+
+	device := strings.SplitN(fields[0], ":", 2)
+	if len(device) != 2 {
+		return ioMax, fmt.Errorf("invalid io max %q: device must be MAJOR:MINOR", limit)
+	}
+	major, err := strconv.ParseUint(device[0], 10, 32)
+	if err != nil {
+		return ioMax, fmt.Errorf("invalid io max %q: invalid major number %q", limit, device[0])
+	}
+	minor, err := strconv.ParseUint(device[1], 10, 32)
+	if err != nil {
+		return ioMax, fmt.Errorf("invalid io max %q: invalid minor number %q", limit, device[1])
+	}
+	ioMax.Major, ioMax.Minor = uint32(major), uint32(minor)
+
+	for _, field := range fields[1:] {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return ioMax, fmt.Errorf("invalid io max %q: invalid field %q", limit, field)
+		}
+		var value uint64
+		if kv[1] != "max" {
+			if value, err = strconv.ParseUint(kv[1], 10, 64); err != nil || value == 0 {
+				return ioMax, fmt.Errorf("invalid io max %q: invalid value for %s", limit, kv[0])
+			}
+		}
+		switch kv[0] {
+		case "rbps":
+			ioMax.Rbps = value
+		case "wbps":
+			ioMax.Wbps = value
+		case "riops":
+			ioMax.Riops = value
+		case "wiops":
+			ioMax.Wiops = value
+		default:
+			return ioMax, fmt.Errorf("invalid io max %q: unknown limit %q", limit, kv[0])
+		}
+	}
+	return ioMax, nil
+}
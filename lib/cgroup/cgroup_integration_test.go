@@ -7,8 +7,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // TestMountFS checks that the cgroup hierarchy is successfully mounted and
@@ -55,6 +57,104 @@ func TestCreateCgroup(t *testing.T) {
 
 }
 
+// TestCreateCgroupInvalidLimit checks that a cgroup is not left behind when
+// the kernel refuses one of its limits.
+func TestCreateCgroupInvalidLimit(t *testing.T) {
+	testMount(CgroupMountDir, t)
+	defer testUnmount(CgroupMountDir, t)
+
+	config := CgroupConfig{
+		Name:  "testInvalid",
+		Cpu:   100,
+		Io:    100,
+		IoMax: []IoMax{{Major: 4095, Minor: 4095, Rbps: 1024}},
+		Path:  filepath.Join(CgroupMountDir, "testInvalid"),
+	}
+
+	if err := config.Create(); err == nil {
+		t.Error("expected io max of a missing device to be refused")
+	}
+	testVerifyCgroupDeleted(t, config)
+}
+
+// TestKillCgroup starts processes in a cgroup, and verifies that all of them
+// are killed, and the cgroup deleted, with both ways of killing a cgroup.
+func TestKillCgroup(t *testing.T) {
+	testMount(CgroupMountDir, t)
+	defer testUnmount(CgroupMountDir, t)
+
+	for name, kill := range map[string]func(*CgroupConfig) error{
+		"Kill":       (*CgroupConfig).Kill,
+		"KillFrozen": (*CgroupConfig).killFrozen,
+	} {
+		config := CgroupConfig{
+			Name: "test" + name,
+			Path: filepath.Join(CgroupMountDir, "test"+name),
+		}
+		if err := os.Mkdir(config.Path, fileMode); err != nil {
+			t.Fatal(err)
+		}
+
+		// Once moved into the cgroup, the shell forks a child that would
+		// outlive the shell itself.
+		cmd := exec.Command("sh", "-c", "read x; sleep 100 & exec sleep 100")
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := cmd.Start(); err != nil {
+			t.Fatal(err)
+		}
+		if err := setCgroupLimit(filepath.Join(config.Path, procsFile), fmt.Sprintf("%d", cmd.Process.Pid)); err != nil {
+			t.Fatal(err)
+		}
+		stdin.Write([]byte("\n"))
+		go cmd.Wait()
+		time.Sleep(100 * time.Millisecond)
+
+		if err := kill(&config); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+		if err := config.Delete(); err != nil {
+			t.Errorf("%s: %s", name, err)
+		}
+		testVerifyCgroupDeleted(t, config)
+	}
+}
+
+// TestRemoveOrphansPrivateParent checks that only cgroups under the parent of a
+// private hierarchy are removed, and never those of the host at its root.
+func TestRemoveOrphansPrivateParent(t *testing.T) {
+	h, err := MountPrivate(CgroupMountDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+	defer os.Remove(h.Parent)
+
+	if h.Parent != filepath.Join(CgroupMountDir, DefaultParent) {
+		t.Fatalf("unexpected cgroup parent. expected: %s, got: %s", filepath.Join(CgroupMountDir, DefaultParent), h.Parent)
+	}
+
+	name := "5a0c3a4e-8d1e-4a43-9f3b-3f1e2d7c9b10"
+	outside := CgroupConfig{Name: name, Path: filepath.Join(h.MountDir, name)}
+	inside := CgroupConfig{Name: name, Path: filepath.Join(h.Parent, name)}
+	for _, config := range []CgroupConfig{outside, inside} {
+		if err := os.Mkdir(config.Path, fileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer outside.Delete()
+
+	if err := RemoveOrphans(h.Parent, func(string) bool { return true }); err != nil {
+		t.Fatal(err)
+	}
+	testVerifyCgroupDeleted(t, inside)
+	if _, err := os.Stat(outside.Path); err != nil {
+		t.Errorf("expected cgroup outside of the parent to be left alone, got: %s", err)
+	}
+}
+
 func testMount(path string, t *testing.T) {
 	t.Helper()
 	if err := Mount(CgroupMountDir); err != nil {
@@ -3,6 +3,9 @@
 package cgroup
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -37,6 +40,88 @@ func TestGetMem(t *testing.T) {
 	}
 }
 
+func TestGetMemHigh(t *testing.T) {
+	testCases := []testCase{
+		{name: "MemHigh", cgroupConfig: CgroupConfig{MemoryHigh: 2}, expectedResult: "2048", expectedError: false},
+		{name: "MemHighUnlimited", cgroupConfig: CgroupConfig{MemoryHigh: 0}, expectedResult: "max", expectedError: false},
+		{name: "MemHighUnderMin", cgroupConfig: CgroupConfig{MemoryHigh: -10}, expectedResult: "", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := tcase.cgroupConfig.getMemHigh()
+		testCgroupCase(t, tcase, result, err)
+	}
+}
+
+func TestGetMemSwap(t *testing.T) {
+	testCases := []testCase{
+		{name: "MemSwap", cgroupConfig: CgroupConfig{MemorySwap: 4}, expectedResult: "4096", expectedError: false},
+		{name: "MemSwapUnlimited", cgroupConfig: CgroupConfig{MemorySwap: 0}, expectedResult: "max", expectedError: false},
+		{name: "MemSwapUnderMin", cgroupConfig: CgroupConfig{MemorySwap: -1}, expectedResult: "", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := tcase.cgroupConfig.getMemSwapMax()
+		testCgroupCase(t, tcase, result, err)
+	}
+}
+
+func TestGetPids(t *testing.T) {
+	testCases := []testCase{
+		{name: "PidsMax", cgroupConfig: CgroupConfig{Pids: 64}, expectedResult: "64", expectedError: false},
+		{name: "PidsUnlimited", cgroupConfig: CgroupConfig{Pids: 0}, expectedResult: "max", expectedError: false},
+		{name: "PidsUnderMin", cgroupConfig: CgroupConfig{Pids: -5}, expectedResult: "", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := tcase.cgroupConfig.getPidsMax()
+		testCgroupCase(t, tcase, result, err)
+	}
+}
+
+func TestGetCpuset(t *testing.T) {
+	testCases := []testCase{
+		{name: "CpusetSingle", cgroupConfig: CgroupConfig{CpusetCpus: "2"}, expectedResult: "2", expectedError: false},
+		{name: "CpusetList", cgroupConfig: CgroupConfig{CpusetCpus: "0-3,6"}, expectedResult: "0-3,6", expectedError: false},
+		{name: "CpusetReversed", cgroupConfig: CgroupConfig{CpusetCpus: "3-1"}, expectedResult: "", expectedError: true},
+		{name: "CpusetInvalid", cgroupConfig: CgroupConfig{CpusetCpus: "0,a"}, expectedResult: "", expectedError: true},
+		{name: "CpusetEmptyItem", cgroupConfig: CgroupConfig{CpusetCpus: "0,,1"}, expectedResult: "", expectedError: true},
+		{name: "CpusetNegative", cgroupConfig: CgroupConfig{CpusetCpus: "-1"}, expectedResult: "", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := getCpuset(tcase.cgroupConfig.CpusetCpus)
+		testCgroupCase(t, tcase, result, err)
+	}
+}
+
+func TestGetIoMax(t *testing.T) {
+	testCases := []testCase{
+		{name: "IoMax", cgroupConfig: CgroupConfig{IoMax: []IoMax{{Major: 8, Minor: 0, Rbps: 1048576, Wiops: 120}}},
+			expectedResult: "8:0 rbps=1048576 wbps=max riops=max wiops=120", expectedError: false},
+		{name: "IoMaxNone", cgroupConfig: CgroupConfig{}, expectedResult: "", expectedError: false},
+		{name: "IoMaxDuplicate", cgroupConfig: CgroupConfig{IoMax: []IoMax{{Major: 8}, {Major: 8}}},
+			expectedResult: "", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := tcase.cgroupConfig.getIoMax()
+		testCgroupCase(t, tcase, strings.Join(result, "\n"), err)
+	}
+}
+
+func TestParseIoMax(t *testing.T) {
+	ioMax, err := ParseIoMax("8:16 rbps=1048576 wbps=max wiops=120")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := IoMax{Major: 8, Minor: 16, Rbps: 1048576, Wiops: 120}
+	if ioMax != expected {
+		t.Errorf("expected: %+v, got: %+v", expected, ioMax)
+	}
+
+	for _, limit := range []string{"", "8 rbps=1", "8:a rbps=1", "8:0 rbps", "8:0 rbps=0", "8:0 rbps=-1", "8:0 dbps=1"} {
+		if _, err := ParseIoMax(limit); err == nil {
+			t.Errorf("error expected for io max %q, but was not received", limit)
+		}
+	}
+}
+
 func TestGetIo(t *testing.T) {
 	testCases := []testCase{
 		{name: "IoMax", cgroupConfig: CgroupConfig{Io: 50}, expectedResult: "default 50", expectedError: false},
@@ -48,6 +133,119 @@ func TestGetIo(t *testing.T) {
 		testCgroupCase(t, tcase, result, err)
 	}
 }
+func TestParseProcs(t *testing.T) {
+	pids, err := parseProcs([]byte("12\n345\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pids) != 2 || pids[0] != 12 || pids[1] != 345 {
+		t.Errorf("expected: [12 345], got: %v", pids)
+	}
+
+	pids, err = parseProcs([]byte(""))
+	if err != nil || len(pids) != 0 {
+		t.Errorf("expected no PIDs, got: %v (%v)", pids, err)
+	}
+
+	if _, err := parseProcs([]byte("12\nabc\n")); err == nil {
+		t.Errorf("error expected for invalid PID, but was not received")
+	}
+}
+
+func TestParseKeyedValue(t *testing.T) {
+	data := []byte("populated 1\nfrozen 0\n")
+	value, err := parseKeyedValue(data, "frozen")
+	if err != nil || value != 0 {
+		t.Errorf("expected: 0, got: %d (%v)", value, err)
+	}
+	value, err = parseKeyedValue(data, "populated")
+	if err != nil || value != 1 {
+		t.Errorf("expected: 1, got: %d (%v)", value, err)
+	}
+	if _, err := parseKeyedValue(data, "missing"); err == nil {
+		t.Errorf("error expected for missing key, but was not received")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := CgroupConfig{Cpu: 50, Io: 50, Pids: 10, CpusetCpus: "0-1", IoMax: []IoMax{{Major: 8, Rbps: 1}}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("received error for valid cgroup: %s", err)
+	}
+
+	invalid := []CgroupConfig{
+		{Cpu: 0, Io: 50},
+		{Cpu: 50, Io: 50, Pids: -1},
+		{Cpu: 50, Io: 50, CpusetMems: "1-0"},
+		{Cpu: 50, Io: 50, IoMax: []IoMax{{Major: 8}, {Major: 8}}},
+	}
+	for _, cg := range invalid {
+		if err := cg.Validate(); err == nil {
+			t.Errorf("error expected for cgroup %+v, but was not received", cg)
+		}
+	}
+}
+
+func TestUpdateLimits(t *testing.T) {
+	cg := CgroupConfig{Name: "test", Cpu: 100, Memory: 0, Io: 100, Path: t.TempDir()}
+	for _, file := range []string{cpuMaxFile, memoryMaxFile, memoryHighFile, ioWeightFile} {
+		if err := os.WriteFile(filepath.Join(cg.Path, file), nil, fileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	readLimit := func(file string) string {
+		data, err := os.ReadFile(filepath.Join(cg.Path, file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	cpu, io := 50, 5
+	if err := cg.UpdateLimits(LimitUpdate{Cpu: &cpu, Io: &io}); err == nil {
+		t.Errorf("error expected for invalid IO setting, but was not received")
+	}
+	if limit := readLimit(cpuMaxFile); limit != "" || cg.Cpu != 100 {
+		t.Errorf("expected cpu limit to be unchanged after invalid update, got: %q (%d)", limit, cg.Cpu)
+	}
+
+	memory := 2
+	if err := cg.UpdateLimits(LimitUpdate{Cpu: &cpu, Memory: &memory}); err != nil {
+		t.Fatal(err)
+	}
+	if limit := readLimit(cpuMaxFile); limit != "50000 100000" || cg.Cpu != 50 {
+		t.Errorf("expected cpu limit: 50000 100000, got: %q (%d)", limit, cg.Cpu)
+	}
+	if limit := readLimit(memoryMaxFile); limit != "2048" || cg.Memory != 2 {
+		t.Errorf("expected memory limit: 2048, got: %q (%d)", limit, cg.Memory)
+	}
+	if limit := readLimit(ioWeightFile); limit != "" || cg.Io != 100 {
+		t.Errorf("expected io limit to be unchanged, got: %q (%d)", limit, cg.Io)
+	}
+}
+
+func TestUpdateLimitsPartial(t *testing.T) {
+	cg := CgroupConfig{Name: "test", Cpu: 100, MemoryHigh: 0, Io: 100, Path: t.TempDir()}
+	// memory.high is missing, so writing it fails.
+	for _, file := range []string{cpuMaxFile, ioWeightFile} {
+		if err := os.WriteFile(filepath.Join(cg.Path, file), nil, fileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cpu, memoryHigh, io := 50, 2, 50
+	err := cg.UpdateLimits(LimitUpdate{Cpu: &cpu, MemoryHigh: &memoryHigh, Io: &io})
+	if err == nil || !strings.Contains(err.Error(), "before the error: cpu") {
+		t.Fatalf("expected an error naming the cpu limit as updated, got: %v", err)
+	}
+	if cg.Cpu != 50 || cg.MemoryHigh != 0 || cg.Io != 100 {
+		t.Errorf("expected only the cpu limit to be updated, got: cpu %d, memory high %d, io %d", cg.Cpu, cg.MemoryHigh, cg.Io)
+	}
+	if data, _ := os.ReadFile(filepath.Join(cg.Path, ioWeightFile)); len(data) != 0 {
+		t.Errorf("expected io limit not to be written after the error, got: %q", data)
+	}
+}
+
 func testCgroupCase(t *testing.T, test testCase, result string, err error) {
 	t.Helper()
 	switch {
@@ -62,3 +260,35 @@ func testCgroupCase(t *testing.T, test testCase, result string, err error) {
 		t.Errorf("test %s: expected: %s, got: %s", test.name, test.expectedResult, result)
 	}
 }
+
+func TestParseIoStat(t *testing.T) {
+	data := []byte("8:0 rbytes=1024 wbytes=512 rios=3 wios=1 dbytes=0 dios=0\n" +
+		"8:16 rbytes=2048 wbytes=0 rios=4 wios=0 dbytes=0 dios=0\n")
+	read, written, err := parseIoStat(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read != 3072 || written != 512 {
+		t.Errorf("expected: 3072 read, 512 written, got: %d read, %d written", read, written)
+	}
+
+	read, written, err = parseIoStat([]byte(""))
+	if err != nil || read != 0 || written != 0 {
+		t.Errorf("expected no io, got: %d read, %d written (%v)", read, written, err)
+	}
+
+	if _, _, err := parseIoStat([]byte("8:0 rbytes=abc\n")); err == nil {
+		t.Errorf("error expected for invalid value, but was not received")
+	}
+}
+
+func TestParseKeyedUint(t *testing.T) {
+	data := []byte("usage_usec 1500\nuser_usec 1000\nsystem_usec 500\n")
+	value, err := parseKeyedUint(data, "usage_usec")
+	if err != nil || value != 1500 {
+		t.Errorf("expected: 1500, got: %d (%v)", value, err)
+	}
+	if _, err := parseKeyedUint([]byte("oom_kill -1\n"), "oom_kill"); err == nil {
+		t.Errorf("error expected for negative value, but was not received")
+	}
+}
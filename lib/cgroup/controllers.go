@@ -0,0 +1,214 @@
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	controllersFile    = "cgroup.controllers"
+	subtreeControlFile = "cgroup.subtree_control"
+)
+
+// Controllers is the set of controllers enabled for the cgroups of jobs.
+type Controllers map[string]bool
+
+// requirement is a limit of a cgroup, and the controller needed to enforce it.
+type requirement struct {
+	limit      string
+	controller string
+	// requested returns true if the cgroup sets the limit.
+	requested func(cg *CgroupConfig) bool
+	// updated returns true if the update changes the limit.
+	updated func(update LimitUpdate) bool
+	// reset removes the limit from the cgroup.
+	reset func(cg *CgroupConfig)
+}
+
+var requirements = []requirement{
+	{
+		limit:      "cpu",
+		controller: "cpu",
+		requested:  func(cg *CgroupConfig) bool { return cg.Cpu != 100 },
+		updated:    func(update LimitUpdate) bool { return update.Cpu != nil },
+		reset:      func(cg *CgroupConfig) { cg.Cpu = 100 },
+	},
+	{
+		limit:      "memory",
+		controller: "memory",
+		requested:  func(cg *CgroupConfig) bool { return cg.Memory != 0 },
+		updated:    func(update LimitUpdate) bool { return update.Memory != nil },
+		reset:      func(cg *CgroupConfig) { cg.Memory = 0 },
+	},
+	{
+		limit:      "memory high",
+		controller: "memory",
+		requested:  func(cg *CgroupConfig) bool { return cg.MemoryHigh != 0 },
+		updated:    func(update LimitUpdate) bool { return update.MemoryHigh != nil },
+		reset:      func(cg *CgroupConfig) { cg.MemoryHigh = 0 },
+	},
+	{
+		limit:      "swap",
+		controller: "memory",
+		requested:  func(cg *CgroupConfig) bool { return cg.MemorySwap != 0 },
+		reset:      func(cg *CgroupConfig) { cg.MemorySwap = 0 },
+	},
+	{
+		limit:      "io",
+		controller: "io",
+		requested:  func(cg *CgroupConfig) bool { return cg.Io != 100 },
+		updated:    func(update LimitUpdate) bool { return update.Io != nil },
+		reset:      func(cg *CgroupConfig) { cg.Io = 100 },
+	},
+	{
+		limit:      "io max",
+		controller: "io",
+		requested:  func(cg *CgroupConfig) bool { return len(cg.IoMax) != 0 },
+		reset:      func(cg *CgroupConfig) { cg.IoMax = nil },
+	},
+	{
+		limit:      "pids",
+		controller: "pids",
+		requested:  func(cg *CgroupConfig) bool { return cg.Pids != 0 },
+		reset:      func(cg *CgroupConfig) { cg.Pids = 0 },
+	},
+	{
+		limit:      "cpus",
+		controller: "cpuset",
+		requested:  func(cg *CgroupConfig) bool { return cg.CpusetCpus != "" },
+		reset:      func(cg *CgroupConfig) { cg.CpusetCpus = "" },
+	},
+	{
+		limit:      "mems",
+		controller: "cpuset",
+		requested:  func(cg *CgroupConfig) bool { return cg.CpusetMems != "" },
+		reset:      func(cg *CgroupConfig) { cg.CpusetMems = "" },
+	},
+}
+
+// EnableControllers enables every controller needed for the limits of jobs in
+// the cgroup.subtree_control of dir, so they are available to the cgroups of
+// jobs created under it. The controllers that were enabled are returned.
+// Missing controllers are logged along with the limits that cannot be
+// enforced without them.
+func EnableControllers(dir string) (Controllers, error) {
+	enabled, err := enableControllers(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, controller := range neededControllers() {
+		if !enabled[controller] {
+			log.Warnf("The %s controller is unavailable. Limits that cannot be enforced: %s",
+				controller, strings.Join(limitsNeeding(controller), ", "))
+			continue
+		}
+		names = append(names, controller)
+	}
+	log.Debugf("Enabled cgroup controllers: %s", strings.Join(names, " "))
+	return enabled, nil
+}
+
+// enableControllers enables every available controller needed for the limits
+// of jobs in the cgroup.subtree_control of dir, and returns the controllers
+// that are enabled.
+func enableControllers(dir string) (Controllers, error) {
+	data, err := os.ReadFile(filepath.Join(dir, controllersFile))
+	if err != nil {
+		return nil, fmt.Errorf("error reading available controllers: %s", err)
+	}
+	available := parseControllers(data)
+
+	subtreeControl := filepath.Join(dir, subtreeControlFile)
+	for _, controller := range neededControllers() {
+		if !available[controller] {
+			continue
+		}
+		// Controllers are enabled one at a time, so one that cannot be
+		// enabled does not prevent the others.
+		if err := setCgroupLimit(subtreeControl, "+"+controller); err != nil {
+			log.Warnf("Unable to enable the %s controller in %s: %s", controller, dir, err)
+		}
+	}
+
+	data, err = os.ReadFile(subtreeControl)
+	if err != nil {
+		return nil, fmt.Errorf("error reading enabled controllers: %s", err)
+	}
+	return parseControllers(data), nil
+}
+
+// Unsupported returns the limits set by the cgroup that need a controller
+// which is not enabled.
+func (c Controllers) Unsupported(cg *CgroupConfig) []string {
+	unsupported := []string{}
+	for _, req := range requirements {
+		if req.requested(cg) && !c[req.controller] {
+			unsupported = append(unsupported, req.limit)
+		}
+	}
+	return unsupported
+}
+
+// UnsupportedUpdate returns the limits changed by the update that need a
+// controller which is not enabled.
+func (c Controllers) UnsupportedUpdate(update LimitUpdate) []string {
+	unsupported := []string{}
+	for _, req := range requirements {
+		if req.updated != nil && req.updated(update) && !c[req.controller] {
+			unsupported = append(unsupported, req.limit)
+		}
+	}
+	return unsupported
+}
+
+// Degrade removes the limits set by the cgroup that need a controller which
+// is not enabled. The limits that were removed are returned.
+func (c Controllers) Degrade(cg *CgroupConfig) []string {
+	removed := c.Unsupported(cg)
+	for _, req := range requirements {
+		if req.requested(cg) && !c[req.controller] {
+			req.reset(cg)
+		}
+	}
+	return removed
+}
+
+// neededControllers returns the controllers needed by any limit, in order.
+func neededControllers() []string {
+	controllers := []string{}
+	seen := map[string]bool{}
+	for _, req := range requirements {
+		if !seen[req.controller] {
+			seen[req.controller] = true
+			controllers = append(controllers, req.controller)
+		}
+	}
+	return controllers
+}
+
+// limitsNeeding returns the limits that need the controller.
+func limitsNeeding(controller string) []string {
+	limits := []string{}
+	for _, req := range requirements {
+		if req.controller == controller {
+			limits = append(limits, req.limit)
+		}
+	}
+	return limits
+}
+
+// parseControllers returns the set of controllers listed in the contents of
+// cgroup.controllers or cgroup.subtree_control.
+func parseControllers(data []byte) Controllers {
+	controllers := Controllers{}
+	for _, controller := range strings.Fields(string(data)) {
+		controllers[controller] = true
+	}
+	return controllers
+}
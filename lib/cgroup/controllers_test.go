@@ -0,0 +1,54 @@
+// +build unit
+
+package cgroup
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseControllers(t *testing.T) {
+	controllers := parseControllers([]byte("cpuset cpu io memory pids\n"))
+	for _, controller := range []string{"cpuset", "cpu", "io", "memory", "pids"} {
+		if !controllers[controller] {
+			t.Errorf("expected controller %s to be enabled", controller)
+		}
+	}
+	if len(parseControllers([]byte(""))) != 0 {
+		t.Errorf("expected no controllers for empty file")
+	}
+}
+
+func TestControllersUnsupported(t *testing.T) {
+	controllers := Controllers{"cpu": true, "io": true}
+	cg := CgroupConfig{Cpu: 50, Memory: 1024, MemoryHigh: 512, Io: 100, Pids: 10}
+
+	expected := []string{"memory", "memory high", "pids"}
+	if unsupported := controllers.Unsupported(&cg); !reflect.DeepEqual(unsupported, expected) {
+		t.Errorf("expected: %v, got: %v", expected, unsupported)
+	}
+
+	cpu := 20
+	update := LimitUpdate{Cpu: &cpu, Memory: &cpu}
+	expected = []string{"memory"}
+	if unsupported := controllers.UnsupportedUpdate(update); !reflect.DeepEqual(unsupported, expected) {
+		t.Errorf("expected: %v, got: %v", expected, unsupported)
+	}
+}
+
+func TestControllersDegrade(t *testing.T) {
+	controllers := Controllers{"memory": true}
+	cg := CgroupConfig{Cpu: 50, Memory: 1024, Io: 20, CpusetCpus: "0", IoMax: []IoMax{{Major: 8}}}
+
+	expected := []string{"cpu", "io", "io max", "cpus"}
+	if removed := controllers.Degrade(&cg); !reflect.DeepEqual(removed, expected) {
+		t.Errorf("expected: %v, got: %v", expected, removed)
+	}
+	degraded := CgroupConfig{Cpu: 100, Memory: 1024, Io: 100}
+	if !reflect.DeepEqual(cg, degraded) {
+		t.Errorf("expected: %+v, got: %+v", degraded, cg)
+	}
+	if unsupported := controllers.Unsupported(&cg); len(unsupported) != 0 {
+		t.Errorf("expected no unsupported limits after degrading, got: %v", unsupported)
+	}
+}
@@ -0,0 +1,195 @@
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultParent is the cgroup created at the root of a private hierarchy
+	// for the cgroups of jobs. The root of a private hierarchy is the root of
+	// the host's hierarchy, so jobs are kept apart from the host's cgroups.
+	DefaultParent = "rjob"
+	// DefaultSubtree is the cgroup created under the server's own cgroup for
+	// the cgroups of jobs, when using a delegated hierarchy.
+	DefaultSubtree = "jobs"
+	// serverLeaf is the cgroup the server moves itself into when using a
+	// delegated hierarchy. Controllers can only be enabled for the children
+	// of a cgroup without processes of its own.
+	serverLeaf = "server"
+
+	mountinfoPath  = "/proc/self/mountinfo"
+	procCgroupPath = "/proc/self/cgroup"
+)
+
+// Hierarchy is the part of a cgroup2 hierarchy where the cgroups of jobs are
+// created.
+type Hierarchy struct {
+	// MountDir is where the cgroup2 filesystem is mounted.
+	MountDir string
+	// Parent is the cgroup under which the cgroups of jobs are created.
+	Parent string
+
+	// base is the highest cgroup rjob may enable controllers in.
+	base string
+	// private is set when the hierarchy was mounted by rjob.
+	private bool
+}
+
+// MountPrivate mounts a new cgroup2 filesystem at mountDir, and creates the
+// cgroups of jobs under parent, relative to the root of the hierarchy.
+func MountPrivate(mountDir, parent string) (*Hierarchy, error) {
+	if parent == "" {
+		parent = DefaultParent
+	}
+	if err := Mount(mountDir); err != nil {
+		return nil, err
+	}
+	h := &Hierarchy{
+		MountDir: mountDir,
+		Parent:   filepath.Join(mountDir, parent),
+		base:     mountDir,
+		private:  true,
+	}
+	if err := os.MkdirAll(h.Parent, dirMode); err != nil {
+		h.Close()
+		return nil, fmt.Errorf("error creating cgroup %s: %s", h.Parent, err)
+	}
+	return h, nil
+}
+
+// Delegated uses the cgroup2 hierarchy that the server is already running in,
+// such as one managed by systemd. The cgroups of jobs are created in subtree,
+// under the server's own cgroup, which must have been delegated to the server,
+// for example with Delegate=yes. The server moves itself into a leaf cgroup
+// next to subtree.
+func Delegated(subtree string) (*Hierarchy, error) {
+	if subtree == "" {
+		subtree = DefaultSubtree
+	}
+
+	procCgroup, err := os.ReadFile(procCgroupPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", procCgroupPath, err)
+	}
+	own, err := parseProcCgroup(procCgroup)
+	if err != nil {
+		return nil, err
+	}
+	// A server restarted from within its leaf, rather than by the service
+	// manager, keeps using the cgroup delegated to it.
+	if filepath.Base(own) == serverLeaf {
+		own = filepath.Dir(own)
+	}
+
+	mountinfo, err := os.ReadFile(mountinfoPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", mountinfoPath, err)
+	}
+	mountDir, mountRoot, err := parseMountinfo(mountinfo, own)
+	if err != nil {
+		return nil, err
+	}
+	// The cgroup is relative to the root of the hierarchy, which may not be
+	// the root of the mount.
+	ownDir := filepath.Join(mountDir, strings.TrimPrefix(own, mountRoot))
+
+	h := &Hierarchy{
+		MountDir: mountDir,
+		Parent:   filepath.Join(ownDir, subtree),
+		base:     ownDir,
+	}
+	log.Debugf("Using delegated cgroup %s", ownDir)
+
+	leaf := &CgroupConfig{Name: serverLeaf, Path: filepath.Join(ownDir, serverLeaf)}
+	if err := os.Mkdir(leaf.Path, dirMode); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("error creating cgroup %s: %s", leaf.Path, err)
+	}
+	if err := leaf.AddProcess(os.Getpid()); err != nil {
+		return nil, fmt.Errorf("unable to move server out of cgroup %s: %s", ownDir, err)
+	}
+	if err := os.Mkdir(h.Parent, dirMode); err != nil && !os.IsExist(err) {
+		return nil, fmt.Errorf("error creating cgroup %s: %s", h.Parent, err)
+	}
+	return h, nil
+}
+
+// EnableControllers enables the controllers needed for the limits of jobs in
+// every cgroup from the highest one rjob manages down to Parent. The
+// controllers available to the cgroups of jobs are returned.
+func (h *Hierarchy) EnableControllers() (Controllers, error) {
+	rel, err := filepath.Rel(h.base, h.Parent)
+	if err != nil {
+		return nil, fmt.Errorf("cgroup %s is not under %s", h.Parent, h.base)
+	}
+	dir := h.base
+	if rel != "." {
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			if _, err := enableControllers(dir); err != nil {
+				return nil, err
+			}
+			dir = filepath.Join(dir, name)
+		}
+	}
+	return EnableControllers(h.Parent)
+}
+
+// Close unmounts the hierarchy if it was mounted by rjob.
+func (h *Hierarchy) Close() error {
+	if !h.private {
+		return nil
+	}
+	return Umount(h.MountDir)
+}
+
+// AddProcess moves the process with the given PID into the cgroup.
+func (cg *CgroupConfig) AddProcess(pid int) error {
+	return setCgroupLimit(filepath.Join(cg.Path, procsFile), fmt.Sprintf("%d", pid))
+}
+
+// parseMountinfo returns the mount point and root of the cgroup2 filesystem in
+// the contents of /proc/self/mountinfo that includes cgroup. When several
+// mounts include it, the one mounted from the deepest root is used.
+func parseMountinfo(data []byte, cgroup string) (string, string, error) {
+	mountDir, mountRoot := "", ""
+	for _, line := range strings.Split(string(data), "\n") {
+		// The optional fields before the separator vary in number.
+		parts := strings.SplitN(line, " - ", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fields, fsFields := strings.Fields(parts[0]), strings.Fields(parts[1])
+		if len(fields) < 5 || len(fsFields) < 1 || fsFields[0] != "cgroup2" {
+			continue
+		}
+		root := fields[3]
+		if !isCgroupUnder(cgroup, root) || (mountDir != "" && len(root) <= len(mountRoot)) {
+			continue
+		}
+		mountDir, mountRoot = fields[4], root
+	}
+	if mountDir == "" {
+		return "", "", fmt.Errorf("no cgroup2 hierarchy including %s is mounted", cgroup)
+	}
+	return mountDir, mountRoot, nil
+}
+
+// isCgroupUnder returns true if cgroup is root or one of its descendants.
+func isCgroupUnder(cgroup, root string) bool {
+	return root == "/" || cgroup == root || strings.HasPrefix(cgroup, root+"/")
+}
+
+// parseProcCgroup returns the cgroup2 cgroup from the contents of
+// /proc/self/cgroup.
+func parseProcCgroup(data []byte) (string, error) {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimPrefix(line, "0::"), nil
+		}
+	}
+	return "", fmt.Errorf("process is not in a cgroup2 hierarchy")
+}
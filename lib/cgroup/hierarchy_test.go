@@ -0,0 +1,53 @@
+// +build unit
+
+package cgroup
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseMountinfo(t *testing.T) {
+	data := []byte("32 24 0:28 / /sys/fs/cgroup rw,relatime - tmpfs tmpfs rw,mode=755\n" +
+		"33 32 0:29 / /sys/fs/cgroup/cpu rw,relatime - cgroup cgroup rw,cpu\n" +
+		"41 32 0:38 /other /tmp/other rw,relatime - cgroup2 cgroup2 rw\n" +
+		"42 32 0:38 /ns /sys/fs/cgroup/unified rw,relatime shared:9 master:1 - cgroup2 cgroup2 rw\n" +
+		"43 32 0:38 / /tmp/root rw,relatime - cgroup2 cgroup2 rw\n")
+	mountDir, root, err := parseMountinfo(data, "/ns/rjob.service")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mountDir != "/sys/fs/cgroup/unified" || root != "/ns" {
+		t.Errorf("expected: /sys/fs/cgroup/unified mounted from /ns, got: %s mounted from %s", mountDir, root)
+	}
+
+	mountDir, root, err = parseMountinfo(data, "/nsother")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mountDir != "/tmp/root" || root != "/" {
+		t.Errorf("expected: /tmp/root mounted from /, got: %s mounted from %s", mountDir, root)
+	}
+
+	if _, _, err := parseMountinfo(data[:len(data)/3], "/ns"); err == nil {
+		t.Errorf("error expected without a cgroup2 mount, but was not received")
+	}
+	if _, _, err := parseMountinfo(data[:bytes.Index(data, []byte("42 "))], "/elsewhere"); err == nil {
+		t.Errorf("error expected without a cgroup2 mount including the cgroup, but was not received")
+	}
+}
+
+func TestParseProcCgroup(t *testing.T) {
+	data := []byte("4:memory:/user.slice\n0::/system.slice/rjob.service\n")
+	own, err := parseProcCgroup(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if own != "/system.slice/rjob.service" {
+		t.Errorf("expected: /system.slice/rjob.service, got: %s", own)
+	}
+
+	if _, err := parseProcCgroup([]byte("4:memory:/user.slice\n")); err == nil {
+		t.Errorf("error expected without a cgroup2 cgroup, but was not received")
+	}
+}
@@ -0,0 +1,166 @@
+package cgroup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	cpuStatFile       = "cpu.stat"
+	memoryCurrentFile = "memory.current"
+	memoryPeakFile    = "memory.peak"
+	memoryEventsFile  = "memory.events"
+	ioStatFile        = "io.stat"
+)
+
+// Usage holds the resources used by the processes of a cgroup. Memory and io
+// usage are left at zero when their controllers are not enabled.
+type Usage struct {
+	// CPU time in microseconds.
+	CpuUsec    uint64 `json:"cpu_usec"`
+	UserUsec   uint64 `json:"user_usec"`
+	SystemUsec uint64 `json:"system_usec"`
+
+	// Memory in bytes. MemoryPeak is only available from Linux 5.19, and is
+	// left at zero on older kernels.
+	MemoryCurrent uint64 `json:"memory_current"`
+	MemoryPeak    uint64 `json:"memory_peak"`
+	// OomKills is the number of processes killed for exceeding the memory
+	// limit.
+	OomKills uint64 `json:"oom_kills"`
+
+	// Bytes read from and written to block devices.
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+}
+
+// Usage reads the resources used by the processes of the cgroup.
+func (cg *CgroupConfig) Usage() (Usage, error) {
+	usage := Usage{}
+
+	cpuStat, err := cg.readFile(cpuStatFile)
+	if err != nil {
+		return usage, err
+	}
+	for key, value := range map[string]*uint64{
+		"usage_usec":  &usage.CpuUsec,
+		"user_usec":   &usage.UserUsec,
+		"system_usec": &usage.SystemUsec,
+	} {
+		if *value, err = parseKeyedUint(cpuStat, key); err != nil {
+			return usage, fmt.Errorf("error parsing %s: %s", cpuStatFile, err)
+		}
+	}
+
+	// Memory and io usage are only available when their controllers are
+	// enabled for the cgroup.
+	if cg.hasFile(memoryCurrentFile) {
+		if usage.MemoryCurrent, err = cg.readUint(memoryCurrentFile); err != nil {
+			return usage, err
+		}
+		memoryEvents, err := cg.readFile(memoryEventsFile)
+		if err != nil {
+			return usage, err
+		}
+		if usage.OomKills, err = parseKeyedUint(memoryEvents, "oom_kill"); err != nil {
+			return usage, fmt.Errorf("error parsing %s: %s", memoryEventsFile, err)
+		}
+	}
+	if cg.hasFile(memoryPeakFile) {
+		if usage.MemoryPeak, err = cg.readUint(memoryPeakFile); err != nil {
+			return usage, err
+		}
+	}
+
+	if cg.hasFile(ioStatFile) {
+		ioStat, err := cg.readFile(ioStatFile)
+		if err != nil {
+			return usage, err
+		}
+		if usage.ReadBytes, usage.WriteBytes, err = parseIoStat(ioStat); err != nil {
+			return usage, fmt.Errorf("error parsing %s: %s", ioStatFile, err)
+		}
+	}
+
+	return usage, nil
+}
+
+// hasFile returns true if the cgroup has the file.
+func (cg *CgroupConfig) hasFile(name string) bool {
+	_, err := os.Stat(filepath.Join(cg.Path, name))
+	return err == nil
+}
+
+// readFile returns the contents of one of the cgroup's files.
+func (cg *CgroupConfig) readFile(name string) ([]byte, error) {
+	path := filepath.Join(cg.Path, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %s", path, err)
+	}
+	return data, nil
+}
+
+// readUint returns the value of a cgroup file holding a single number.
+func (cg *CgroupConfig) readUint(name string) (uint64, error) {
+	data, err := cg.readFile(name)
+	if err != nil {
+		return 0, err
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("error parsing %s: %s", name, err)
+	}
+	return value, nil
+}
+
+// parseKeyedUint returns the value of key from the contents of a flat keyed
+// cgroup file, such as cpu.stat.
+func parseKeyedUint(data []byte, key string) (uint64, error) {
+	value, err := parseKeyedValue(data, key)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, fmt.Errorf("negative value for key %s", key)
+	}
+	return uint64(value), nil
+}
+
+// parseIoStat returns the bytes read and written across all devices from the
+// contents of io.stat. Each line holds the counters of one device, such as
+// "8:0 rbytes=90430464 wbytes=299008 rios=8950 wios=12 dbytes=0 dios=0".
+func parseIoStat(data []byte) (uint64, uint64, error) {
+	var read, written uint64
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		for _, field := range fields[1:] {
+			kv := strings.SplitN(field, "=", 2)
+			if len(kv) != 2 {
+				return 0, 0, fmt.Errorf("invalid field %q", field)
+			}
+			key, value := kv[0], kv[1]
+			var counter *uint64
+			switch key {
+			case "rbytes":
+				counter = &read
+			case "wbytes":
+				counter = &written
+			default:
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid value for %s of device %s: %s", key, fields[0], err)
+			}
+			*counter += n
+		}
+	}
+	return read, written, nil
+}
@@ -1,26 +1,32 @@
 package command
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
-	"path/filepath"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/bill-rich/rjob/lib/cgroup"
+	"github.com/bill-rich/rjob/lib/network"
+	"github.com/bill-rich/rjob/lib/rootfs"
+	"github.com/bill-rich/rjob/lib/security"
+	"github.com/creack/pty"
 	log "github.com/sirupsen/logrus"
+	"golang.org/x/sys/unix"
 )
 
-// TODO: Add logging to goroutine
 // TODO: Figure out re-execing processes for cgroup
-// TODO: Redirect/capture stderr
 
 const (
-	OutRefresh       = 1
-	JobStatusStopped = "STOPPED"
-	JobStatusRunning = "RUNNING"
+	OutRefresh = 1
 )
 
 type JobConfig struct {
@@ -30,26 +36,123 @@ type JobConfig struct {
 	CgroupConfig *cgroup.CgroupConfig
 	Owner        string
 
+	// Tty starts the job on a new pseudo-terminal instead of pipes. The
+	// terminal is made available as Input, and everything written to it is
+	// captured in Output.
+	Tty bool
+	Pty *os.File
+
+	// Stdin, Stdout and Stderr are used as the job's standard streams when
+	// set. Otherwise pipes are created for the output, which is captured in
+	// Output and ErrOutput. Without Stdin, the job reads from /dev/null,
+	// unless OpenStdin is set. A pipe is then created for stdin, and its
+	// write end is made available as Input.
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	OpenStdin bool
+	Input     io.WriteCloser
+
+	// Namespaces are the namespaces the job is run in. DefaultNamespaces
+	// are used when it is nil, and none when it is empty. Jobs wrapped by
+	// Start are put in a new cgroup namespace by the wrapper, once it has
+	// moved into the job's cgroup, so that the job's cgroup is the root of
+	// the namespace.
+	Namespaces []string
+	// Rootless runs the job in a new user namespace, in which the user
+	// running rjob is mapped to root. This allows the other namespaces of
+	// the job to be created without privileges.
+	Rootless bool
+
+	// Network is the network mode of the job. It defaults to
+	// network.ModeNone for jobs in a net namespace, and network.ModeHost
+	// otherwise. Jobs in a net namespace have their network configured by
+	// the wrapper started by Start.
+	Network string
+	// SetupNetwork is called once the wrapper of a job in a net namespace
+	// has been started, with the PID of the wrapper. It sets up the host's
+	// side of the job's network, and returns the configuration the wrapper
+	// applies inside the namespace.
+	SetupNetwork func(pid int) (network.Config, error)
+
+	// Credential is the identity the job's command runs as. The command
+	// inherits the identity of rjob when it is nil. The wrapper started by
+	// Start keeps running as root, so that it can prepare the job's
+	// namespaces, and runs the command with the credential.
+	Credential *syscall.Credential
+
+	// Profile restricts the capabilities and system calls of the job's
+	// command. The wrapper started by Start applies it right before it
+	// starts the command.
+	Profile *security.Profile
+
+	// MountProc makes mount propagation private and mounts a new /proc
+	// before the command is started, so that /proc matches the PID
+	// namespace. It is used by the wrapper started by Start, which runs in
+	// the job's mount and PID namespaces.
+	MountProc bool
+
+	// Rootfs is the root filesystem of the job. Jobs use the host's
+	// filesystem when it is nil. The wrapper started by Start pivots into it
+	// before running the job's command, which requires a mount namespace.
+	Rootfs *rootfs.Config
+
+	// BeforeStart is called by Run right before the command is started.
+	// rjob's reexec operation uses it to configure the job's network and
+	// root filesystem.
+	BeforeStart func() error
+
+	// Report is used by rjob's reexec operation to report the state of the
+	// command back to the server.
+	Report *os.File
+
 	Cmd *exec.Cmd
 
-	Output   *OutputBuffer
-	Status   string
-	ExitCode int
+	// reexecPath is set by Start to the rjob executable used to wrap the job.
+	reexecPath string
+	reportPipe *os.File
+	reports    *json.Decoder
+
+	Output    *OutputBuffer
+	ErrOutput *OutputBuffer
+	// Records receives the output of both streams framed into timestamped
+	// lines, when set. See OutputRecord.
+	Records  *OutputBuffer
+	recorder *recorder
+
+	// JobState is only updated while holding mu. Use State to read it while
+	// the job is running.
+	JobState
+	mu   sync.Mutex
+	done chan struct{}
+	// started is closed by Run once it has recorded whether the job started.
+	// The job's end is not recorded before that.
+	started   chan struct{}
+	capturing sync.WaitGroup
 }
 
 // Wrap creates the required cgroup for the new job, and uses rjob's reexec
 // option to start a job within a cgroup to avoid any escaping.
+//
+// A job whose cgroup or limits cannot be created is not run. It is marked as
+// failed to start, and the error is returned.
 func (job *JobConfig) Start() error {
-	job.CgroupConfig.Create()
+	if err := job.CgroupConfig.Create(); err != nil {
+		job.done = make(chan struct{})
+		err = fmt.Errorf("unable to create cgroup: %s", err)
+		job.setFailedToStart(err)
+		return err
+	}
 
 	self, err := os.Executable()
 	if err != nil {
-		return fmt.Errorf("cannot get path of rjob executable: %s", err)
+		job.done = make(chan struct{})
+		err = fmt.Errorf("cannot get path of rjob executable: %s", err)
+		job.setFailedToStart(err)
+		return err
 	}
-	originalCommand := job.Command
-	job.Command = self
-	job.Args = append([]string{"reexec", job.CgroupName, originalCommand}, job.Args...)
-	log.Debug("Wrapping command as ", job.Command, " ", strings.Join(job.Args, " "))
+	job.reexecPath = self
+	log.Debug("Wrapping command as ", strings.Join(job.command().Args, " "))
 
 	if err := job.Run(); err != nil {
 		return err
@@ -58,99 +161,489 @@ func (job *JobConfig) Start() error {
 	return nil
 }
 
-// Run will execute a command in a job. If CgroupName is included in the config,
-// the current process will first move itself to that cgroup. This is to ensure
+// Run will execute a command in a job. If CgroupConfig is included in the
+// config, the current process will first move itself to that cgroup. This is to ensure
 // that no processes escape the cgroup.
+//
+// Run returns once the job is running. If the job could not be started, it is
+// marked as failed to start, and the error is returned.
 func (job *JobConfig) Run() error {
-	if job.CgroupName != "" {
+	job.done = make(chan struct{})
+	job.started = make(chan struct{})
+	defer close(job.started)
+	job.mu.Lock()
+	job.Status = JobStatusPending
+	job.mu.Unlock()
+
+	if err := job.start(); err != nil {
+		job.sendReport(report{Error: err.Error()})
+		job.setFailedToStart(err)
+		return err
+	}
+	job.sendReport(report{Started: true})
+	job.setRunning()
+	return nil
+}
+
+// start starts the job's process along with a goroutine waiting for it.
+func (job *JobConfig) start() error {
+	// Jobs wrapped by Start are moved into their cgroup by the wrapper. Moving
+	// the current process would place the server itself in the job's cgroup.
+	if job.CgroupConfig != nil && job.reexecPath == "" {
 		if err := job.ChangeCgroup(); err != nil {
 			return fmt.Errorf("unable to move process into cgroup: %s", err)
 		}
 	}
 
-	// Kick off a goroutine to run the job and update the output.
-	go func() {
-		job.Cmd = exec.Command(job.Command, job.Args...)
-		// Allowing which namespaces to use would be a nice future feature.
-		job.Cmd.SysProcAttr = &syscall.SysProcAttr{Cloneflags: syscall.CLONE_NEWPID | syscall.CLONE_NEWNS | syscall.CLONE_NEWNET}
+	if job.BeforeStart != nil {
+		if err := job.BeforeStart(); err != nil {
+			return err
+		}
+	}
+	if job.MountProc {
+		if err := mountProc(); err != nil {
+			return err
+		}
+	}
 
+	job.Cmd = job.command()
+	namespaces := job.namespaces()
+	if err := network.ValidateMode(job.network()); err != nil {
+		return err
+	}
+	if (job.network() == network.ModeHost) == HasNamespace(namespaces, NamespaceNet) {
+		return fmt.Errorf("network mode %s cannot be used with the namespaces %v", job.network(), namespaces)
+	}
+	if job.Rootfs != nil && !HasNamespace(namespaces, NamespaceMount) {
+		return fmt.Errorf("a root filesystem requires the %s namespace", NamespaceMount)
+	}
+	if job.reexecPath != "" {
+		// The wrapper creates the cgroup namespace for the command it runs.
+		namespaces = withoutNamespace(namespaces, NamespaceCgroup)
+	}
+	flags, err := cloneflags(namespaces)
+	if err != nil {
+		return err
+	}
+	job.Cmd.SysProcAttr = &syscall.SysProcAttr{Cloneflags: flags}
+	if job.Credential != nil && job.reexecPath == "" {
+		credential := *job.Credential
+		// Supplementary groups cannot be changed in user namespaces where
+		// setgroups is denied.
+		credential.NoSetGroups = setgroupsDenied()
+		job.Cmd.SysProcAttr.Credential = &credential
+	}
+	if HasNamespace(namespaces, NamespaceUser) || (job.Rootless && job.reexecPath != "") {
+		job.useUserNamespace()
+	}
+
+	if job.Output == nil {
 		job.Output = &OutputBuffer{}
+	}
+	if job.ErrOutput == nil {
+		job.ErrOutput = &OutputBuffer{}
+	}
+	if job.Records != nil {
+		job.recorder = &recorder{buf: job.Records}
+	}
 
-		out, _ := job.Cmd.StdoutPipe()
-		err := job.Cmd.Start()
-		if err != nil {
-			log.Error(err)
+	var reportWriter, networkWriter *os.File
+	if job.reexecPath != "" {
+		if reportWriter, err = job.openReportPipe(); err != nil {
+			return err
+		}
+		if HasNamespace(namespaces, NamespaceNet) {
+			if networkWriter, err = job.openNetworkPipe(); err != nil {
+				job.reportPipe.Close()
+				reportWriter.Close()
+				return err
+			}
+		}
+	}
+
+	startCommand := job.startPiped
+	if job.Tty {
+		startCommand = job.startTty
+	}
+	if job.Profile != nil && job.reexecPath == "" {
+		err = job.startConfined(startCommand)
+	} else {
+		err = startCommand()
+	}
+	if reportWriter != nil {
+		reportWriter.Close()
+	}
+	if networkWriter != nil {
+		// The read end belongs to the wrapper now.
+		job.Cmd.ExtraFiles[len(job.Cmd.ExtraFiles)-1].Close()
+	}
+	if err != nil {
+		if job.reportPipe != nil {
+			job.reportPipe.Close()
+		}
+		if networkWriter != nil {
+			networkWriter.Close()
+		}
+		return err
+	}
+
+	go job.wait()
+
+	if networkWriter != nil {
+		// The wrapper exits once the pipe is closed without a
+		// configuration.
+		if err := job.sendNetworkConfig(networkWriter); err != nil {
+			return err
+		}
+	}
+
+	if job.reexecPath != "" {
+		return job.readStartReport()
+	}
+	return nil
+}
+
+// wait waits for the job's process to exit, and records how it ended. Output
+// is captured until the job's end of every stream is closed. A job that exits
+// right away is not recorded as ended until Run has recorded its start, and
+// the reports of the reexec wrapper are only read by wait from then on.
+func (job *JobConfig) wait() {
+	<-job.started
+	job.capturing.Wait()
+	job.Cmd.Wait()
+
+	exitCode, signal := exitStatus(job.Cmd.ProcessState.Sys().(syscall.WaitStatus))
+	if job.reports != nil {
+		// Prefer the exit status of the command over that of the wrapper. A
+		// wrapper that was killed is not able to send a report.
+		if r, ok := job.readExitReport(); ok {
+			exitCode, signal = r.ExitCode, r.Signal
+		}
+		job.reportPipe.Close()
+	}
+
+	if job.Pty != nil {
+		job.Pty.Close()
+	}
+
+	job.sendReport(report{Exited: true, ExitCode: exitCode, Signal: signal})
+	job.setExited(exitCode, signal)
+	log.Debugf("Job (%s) has finished.", job.CgroupName)
+}
+
+// startConfined starts the job's command from a thread restricted by the job's
+// security profile, which the command inherits. The restrictions cannot be
+// lifted, so the thread is never unlocked, and exits along with its goroutine.
+func (job *JobConfig) startConfined(startCommand func() error) error {
+	result := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		if err := job.Profile.Apply(); err != nil {
+			result <- fmt.Errorf("unable to apply profile %s: %s", job.Profile.Name(), err)
+			return
+		}
+		result <- startCommand()
+	}()
+	return <-result
+}
+
+// useUserNamespace starts the job in a new user namespace, mapping the user and
+// group running rjob to root.
+func (job *JobConfig) useUserNamespace() {
+	attr := job.Cmd.SysProcAttr
+	attr.Cloneflags |= syscall.CLONE_NEWUSER
+	attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+	attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+	// Unprivileged users may only map their group once setgroups is denied.
+	attr.GidMappingsEnableSetgroups = false
+
+	// Root may map other IDs as well, so that the job's command can run as
+	// its own user and groups inside the namespace.
+	if job.Credential != nil && os.Getuid() == 0 {
+		attr.UidMappings = addIDMapping(attr.UidMappings, job.Credential.Uid)
+		attr.GidMappings = addIDMapping(attr.GidMappings, job.Credential.Gid)
+		for _, gid := range job.Credential.Groups {
+			attr.GidMappings = addIDMapping(attr.GidMappings, gid)
 		}
-		job.Status = JobStatusRunning
+		attr.GidMappingsEnableSetgroups = true
+	}
+}
+
+// addIDMapping maps id to itself, unless it is already mapped.
+func addIDMapping(mappings []syscall.SysProcIDMap, id uint32) []syscall.SysProcIDMap {
+	for _, mapping := range mappings {
+		if mapping.ContainerID == int(id) {
+			return mappings
+		}
+	}
+	return append(mappings, syscall.SysProcIDMap{ContainerID: int(id), HostID: int(id), Size: 1})
+}
+
+// setgroupsDenied returns true if the current user namespace denies setgroups.
+func setgroupsDenied() bool {
+	data, err := os.ReadFile("/proc/self/setgroups")
+	return err == nil && strings.TrimSpace(string(data)) == "deny"
+}
+
+// command returns the command used to run the job. Jobs started with Start are
+// wrapped by rjob's reexec operation.
+func (job *JobConfig) command() *exec.Cmd {
+	if job.reexecPath == "" {
+		return exec.Command(job.Command, job.Args...)
+	}
+	args := append(job.reexecArgs(), job.CgroupConfig.Path, job.Command)
+	return exec.Command(job.reexecPath, append(args, job.Args...)...)
+}
 
-		for {
-			b := make([]byte, 1)
-			_, err := out.Read(b)
-			job.Output.Write(b)
-			if err != nil {
-				break
+// reexecArgs returns the arguments used to pass the job's options on to rjob's
+// reexec operation. The path of the cgroup, the command, and the command
+// arguments follow them.
+func (job *JobConfig) reexecArgs() []string {
+	args := []string{"reexec", "--reportfd", strconv.Itoa(ReportFd)}
+	if job.Tty {
+		args = append(args, "--tty")
+	}
+	if HasNamespace(job.namespaces(), NamespaceCgroup) {
+		args = append(args, "--cgroupns")
+	}
+	if HasNamespace(job.namespaces(), NamespaceNet) {
+		args = append(args, "--netfd", strconv.Itoa(NetworkFd))
+	}
+	if job.Profile != nil {
+		args = append(args, "--profile", job.Profile.Path)
+	}
+	if job.Credential != nil {
+		args = append(args,
+			"--uid", strconv.FormatUint(uint64(job.Credential.Uid), 10),
+			"--gid", strconv.FormatUint(uint64(job.Credential.Gid), 10))
+		if len(job.Credential.Groups) > 0 {
+			args = append(args, "--groups")
+			for _, gid := range job.Credential.Groups {
+				args = append(args, strconv.FormatUint(uint64(gid), 10))
 			}
 		}
+	}
+	// A root filesystem comes with its own /proc.
+	if HasNamespace(job.namespaces(), NamespacePid) && HasNamespace(job.namespaces(), NamespaceMount) && job.Rootfs == nil {
+		args = append(args, "--proc")
+	}
+	if job.Rootfs != nil {
+		// Lists are given after a single flag, as repeating a flag replaces
+		// its values.
+		args = append(append(args, "--rootfs"), job.Rootfs.Layers...)
+		if len(job.Rootfs.Mounts) > 0 {
+			args = append(args, "--mount")
+			for _, mount := range job.Rootfs.Mounts {
+				args = append(args, mount.String())
+			}
+		}
+	}
+	return append(args, "--")
+}
+
+// startPiped starts the job with its standard streams connected to pipes,
+// unless they were provided in the config, and captures the output.
+func (job *JobConfig) startPiped() error {
+	if job.Stdin != nil {
+		job.Cmd.Stdin = job.Stdin
+	} else if job.OpenStdin {
+		job.Input, _ = job.Cmd.StdinPipe()
+	}
+
+	var out, errOut io.Reader
+	if job.Stdout != nil {
+		job.Cmd.Stdout = job.Stdout
+	} else {
+		out, _ = job.Cmd.StdoutPipe()
+	}
+	if job.Stderr != nil {
+		job.Cmd.Stderr = job.Stderr
+	} else {
+		errOut, _ = job.Cmd.StderrPipe()
+	}
+
+	if err := job.Cmd.Start(); err != nil {
+		return fmt.Errorf("unable to start %s: %s", job.Command, err)
+	}
+
+	if out != nil {
+		job.captureOutput(out, job.Output, StreamStdout)
+	}
+	if errOut != nil {
+		job.captureOutput(errOut, job.ErrOutput, StreamStderr)
+	}
+	return nil
+}
+
+// startTty starts the job on a new pseudo-terminal and captures everything the
+// job writes to it.
+func (job *JobConfig) startTty() error {
+	tty, err := pty.Start(job.Cmd)
+	if err != nil {
+		return fmt.Errorf("unable to start %s: %s", job.Command, err)
+	}
+	job.Pty = tty
+	job.Input = tty
+
+	job.captureOutput(tty, job.Output, StreamStdout)
+	return nil
+}
 
-		log.Infof("Job (%s) has finished.", job.CgroupName)
-		return
+// captureOutput starts a goroutine capturing the output read from r. The job is
+// not marked as ended until r is closed.
+func (job *JobConfig) captureOutput(r io.Reader, buf *OutputBuffer, stream string) {
+	var lines *lineRecorder
+	if job.recorder != nil {
+		lines = &lineRecorder{recorder: job.recorder, stream: stream}
+	}
+	job.capturing.Add(1)
+	go func() {
+		defer job.capturing.Done()
+		captureOutput(r, buf, lines)
 	}()
+}
 
-	// TODO: Replace this with an actual check that job has started.
-	time.Sleep(OutRefresh * time.Second)
+// captureOutput copies everything read from r into buf until r is closed. The
+// lines read are recorded as well, unless lines is nil.
+func captureOutput(r io.Reader, buf *OutputBuffer, lines *lineRecorder) {
+	defer buf.Close()
+	if lines != nil {
+		defer lines.Close()
+	}
+	b := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(b)
+		buf.Write(b[:n])
+		if lines != nil {
+			lines.Write(b[:n])
+		}
+		if err != nil {
+			break
+		}
+	}
+}
 
+// WriteInput sends data to the job's standard input. If closeInput is true,
+// the job's standard input is closed after the data is written.
+func (job *JobConfig) WriteInput(data []byte, closeInput bool) error {
+	if job.Input == nil {
+		return fmt.Errorf("job (%s) does not accept input", job.CgroupName)
+	}
+	if len(data) > 0 {
+		if _, err := job.Input.Write(data); err != nil {
+			return fmt.Errorf("unable to write to job (%s) input: %s", job.CgroupName, err)
+		}
+	}
+	if closeInput {
+		if job.Pty != nil {
+			// Closing the terminal would hang up the job. Send an EOF
+			// character instead.
+			_, err := job.Input.Write([]byte{4})
+			return err
+		}
+		return job.Input.Close()
+	}
 	return nil
 }
 
-// Kill kills the job.
-func (job *JobConfig) Kill() error {
-	return job.Cmd.Process.Kill()
+// Resize changes the window size of the job's terminal.
+func (job *JobConfig) Resize(rows, cols uint16) error {
+	if job.Pty == nil {
+		return fmt.Errorf("job (%s) does not have a terminal", job.CgroupName)
+	}
+	return pty.Setsize(job.Pty, &pty.Winsize{Rows: rows, Cols: cols})
 }
 
-// ChangeCgroup moves the current process into the specified cgroup.
-func (job *JobConfig) ChangeCgroup() error {
-	pid := os.Getpid()
-	cgroupProcs := filepath.Join(cgroup.CgroupMountDir, job.CgroupName, "cgroup.procs")
-	f, err := os.OpenFile(cgroupProcs, os.O_APPEND|os.O_WRONLY, 0555)
-	if err != nil {
-		return fmt.Errorf("unable to open %s: %s", cgroupProcs, err)
+// Stop sends sig to the job, and kills whatever is left of the job once it has
+// ended or the grace period has passed.
+func (job *JobConfig) Stop(sig syscall.Signal, gracePeriod time.Duration) error {
+	if err := job.SendSignal(sig); err != nil {
+		return err
 	}
-	defer f.Close()
 
-	_, err = f.WriteString(fmt.Sprintf("%d", pid))
-	if err != nil {
-		return fmt.Errorf("unable to write PID to %s: %s", cgroupProcs, err)
+	select {
+	case <-job.Done():
+	case <-time.After(gracePeriod):
+		log.Debugf("Job (%s) did not end within %s", job.CgroupName, gracePeriod)
+	}
+
+	if err := job.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
 	}
 	return nil
 }
 
-// UpdateStatus checks if the job is still running. If it is not, the exit code,
-// and status are updated. The method is only called when the jobs are being
-// examined.
-func (job *JobConfig) UpdateStatus() {
-	if !job.IsRunning() {
-		job.Status = JobStatusStopped
-		job.ExitCode = job.Cmd.ProcessState.ExitCode()
+// SendSignal sends sig to every process in the job's cgroup. Jobs without a
+// cgroup only have their own process signalled.
+func (job *JobConfig) SendSignal(sig syscall.Signal) error {
+	if job.CgroupConfig != nil {
+		return job.CgroupConfig.Signal(sig)
+	}
+	if job.Cmd == nil || job.Cmd.Process == nil {
+		return fmt.Errorf("job (%s) is not running", job.CgroupName)
+	}
+	return job.Cmd.Process.Signal(sig)
+}
+
+// Kill kills the job. Every process in the job's cgroup is killed, including
+// any that were started by the job's command and outlived it. A cgroup that
+// no longer exists has no processes left to kill.
+func (job *JobConfig) Kill() error {
+	if job.CgroupConfig != nil {
+		err := job.CgroupConfig.Kill()
+		if _, statErr := os.Stat(job.CgroupConfig.Path); os.IsNotExist(statErr) {
+			// The cgroup is deleted once the job has ended and all of its
+			// processes have exited.
+			return nil
+		}
+		return err
 	}
+	return job.SendSignal(syscall.SIGKILL)
 }
 
-// IsRunning returns true if the job is still running.
-func (job *JobConfig) IsRunning() bool {
-	if out, err := job.Cmd.StdoutPipe(); err != nil && out != nil {
-		return true
+// UpdateLimits changes the resource limits of the job's cgroup.
+func (job *JobConfig) UpdateLimits(update cgroup.LimitUpdate) error {
+	if job.CgroupConfig == nil {
+		return fmt.Errorf("job has no cgroup")
 	}
-	return false
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.CgroupConfig.UpdateLimits(update)
 }
 
-// PrintJPrintJobOutput will print job output as it becomes available.
-func (job *JobConfig) PrintJobOutput() {
-	bytesCurrent := 0
-	var newData string
-	for job.Status == JobStatusRunning || job.Output.HasNew(bytesCurrent) {
-		newData, bytesCurrent = job.Output.Read(bytesCurrent)
-		if newData != "" {
-			fmt.Print(newData)
-			time.Sleep(OutRefresh * time.Second)
+// Limits returns the current resource limits of the job's cgroup.
+func (job *JobConfig) Limits() cgroup.CgroupConfig {
+	if job.CgroupConfig == nil {
+		return cgroup.CgroupConfig{}
+	}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return *job.CgroupConfig
+}
+
+// ParseSignal returns the signal with the given name or number. The SIG prefix
+// of the name is optional.
+func ParseSignal(name string) (syscall.Signal, error) {
+	if num, err := strconv.Atoi(name); err == nil {
+		// Linux supports signal numbers up to 64, including real-time signals.
+		if num < 1 || num > 64 {
+			return 0, fmt.Errorf("invalid signal number: %d", num)
 		}
+		return syscall.Signal(num), nil
+	}
+
+	name = strings.ToUpper(name)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
 	}
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("unknown signal: %s", name)
+	}
+	return sig, nil
+}
+
+// ChangeCgroup moves the current process into the job's cgroup.
+func (job *JobConfig) ChangeCgroup() error {
+	return job.CgroupConfig.AddProcess(os.Getpid())
 }
@@ -3,9 +3,16 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
+
+	"github.com/bill-rich/rjob/lib/cgroup"
 )
 
 func TestCommand(t *testing.T) {
@@ -19,19 +26,116 @@ func TestCommand(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	for i := 0; i <= 10 && config.Status == JobStatusRunning; i++ {
+	for i := 0; i <= 10 && config.IsRunning(); i++ {
 		// TODO: Add timeout
 		time.Sleep(1 * time.Second)
 	}
 
-	output, _ := config.Output.Read(0)
+	output, _, _ := config.Output.Read(0)
 	re := regexp.MustCompile("test")
-	if !re.MatchString(output) {
+	if !re.Match(output) {
 		t.Fatalf("unexpected job output. expected: test, got: %s", output)
 	}
 
 }
 
+func TestCommandStderr(t *testing.T) {
+	config := JobConfig{
+		Command:    "sh",
+		Args:       []string{"-c", "echo out; echo err >&2"},
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= 10 && config.IsRunning(); i++ {
+		time.Sleep(1 * time.Second)
+	}
+
+	output, _, _ := config.Output.Read(0)
+	if string(output) != "out\n" {
+		t.Fatalf("unexpected job stdout. expected: out, got: %s", output)
+	}
+	errOutput, _, _ := config.ErrOutput.Read(0)
+	if string(errOutput) != "err\n" {
+		t.Fatalf("unexpected job stderr. expected: err, got: %s", errOutput)
+	}
+}
+
+func TestCommandInput(t *testing.T) {
+	config := JobConfig{
+		Command:    "cat",
+		CgroupName: "",
+		OpenStdin:  true,
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := config.WriteInput([]byte("test"), true); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= 10 && !config.Output.HasNew(3); i++ {
+		time.Sleep(1 * time.Second)
+	}
+
+	output, _, _ := config.Output.Read(0)
+	if string(output) != "test" {
+		t.Fatalf("unexpected job output. expected: test, got: %s", output)
+	}
+}
+
+func TestCommandNoInput(t *testing.T) {
+	config := JobConfig{
+		Command:    "cat",
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without an open stdin, cat reads /dev/null and exits right away.
+	select {
+	case <-config.Done():
+	case <-time.After(5 * time.Second):
+		config.Kill()
+		t.Fatal("job reading stdin did not exit")
+	}
+	if state := config.State(); state.Status != JobStatusExited || state.ExitCode != 0 {
+		t.Fatalf("unexpected job state. expected: EXITED with 0, got: %s with %d", state.Status, state.ExitCode)
+	}
+	if err := config.WriteInput([]byte("test"), true); err == nil {
+		t.Fatal("expected input to be refused")
+	}
+}
+
+func TestCommandTty(t *testing.T) {
+	config := JobConfig{
+		Command:    "tty",
+		CgroupName: "",
+		Tty:        true,
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i <= 10 && !config.Output.HasNew(0); i++ {
+		time.Sleep(1 * time.Second)
+	}
+
+	output, _, _ := config.Output.Read(0)
+	re := regexp.MustCompile("^/dev/pts/")
+	if !re.Match(output) {
+		t.Fatalf("unexpected job output. expected a terminal, got: %s", output)
+	}
+}
+
 func TestCommandKill(t *testing.T) {
 	config := JobConfig{
 		Command:    "sleep",
@@ -48,18 +152,211 @@ func TestCommandKill(t *testing.T) {
 	}
 
 	for i := 0; i <= 3; i++ {
-		config.UpdateStatus()
-		if config.Status != JobStatusRunning {
+		if !config.IsRunning() {
 			break
 		}
 		time.Sleep(OutRefresh * time.Second)
 	}
 
-	if config.Status == JobStatusRunning {
+	state := config.State()
+	if state.Status == JobStatusRunning {
 		t.Fatalf("job still running after 3 seconds")
 	}
+	if state.Status != JobStatusKilled || state.Signal != "SIGKILL" {
+		t.Fatalf("unexpected job state. expected: KILLED by SIGKILL, got: %s by %s", state.Status, state.Signal)
+	}
+
+}
+
+func TestCommandKillDeletedCgroup(t *testing.T) {
+	config := JobConfig{
+		CgroupName:   "deleted",
+		CgroupConfig: &cgroup.CgroupConfig{Name: "deleted", Path: filepath.Join(t.TempDir(), "deleted")},
+	}
+
+	if err := config.Kill(); err != nil {
+		t.Fatalf("expected killing a deleted cgroup to succeed, got: %s", err)
+	}
+}
+
+func TestCommandStop(t *testing.T) {
+	config := JobConfig{
+		Command:    "sh",
+		Args:       []string{"-c", "trap 'exit 5' TERM; while true; do sleep 0.1; done"},
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(OutRefresh * time.Second)
+
+	if err := config.Stop(syscall.SIGTERM, 5*time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	state := config.State()
+	if state.Status != JobStatusExited || state.ExitCode != 5 {
+		t.Fatalf("unexpected job state. expected: EXITED with 5, got: %s with %d", state.Status, state.ExitCode)
+	}
+}
+
+func TestCommandStopGracePeriod(t *testing.T) {
+	config := JobConfig{
+		Command:    "sh",
+		Args:       []string{"-c", "trap '' TERM; while true; do sleep 0.1; done"},
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(OutRefresh * time.Second)
 
+	if err := config.Stop(syscall.SIGTERM, 1*time.Second); err != nil {
+		t.Fatal(err)
+	}
+	config.Wait()
+
+	state := config.State()
+	if state.Status != JobStatusKilled || state.Signal != "SIGKILL" {
+		t.Fatalf("unexpected job state. expected: KILLED by SIGKILL, got: %s by %s", state.Status, state.Signal)
+	}
+}
+
+func TestCommandExitCode(t *testing.T) {
+	config := JobConfig{
+		Command:    "sh",
+		Args:       []string{"-c", "exit 3"},
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+	config.Wait()
+
+	state := config.State()
+	if state.Status != JobStatusExited || state.ExitCode != 3 {
+		t.Fatalf("unexpected job state. expected: EXITED with 3, got: %s with %d", state.Status, state.ExitCode)
+	}
+	if state.StartTime.IsZero() || state.EndTime.Before(state.StartTime) {
+		t.Fatalf("unexpected job times. started: %s, ended: %s", state.StartTime, state.EndTime)
+	}
+}
+
+func TestCommandExitsRightAway(t *testing.T) {
+	// Jobs ending before Run returns must still end up as EXITED.
+	for i := 0; i < 50; i++ {
+		config := JobConfig{
+			Command:    "true",
+			CgroupName: "",
+		}
+
+		if err := config.Run(); err != nil {
+			t.Fatal(err)
+		}
+		config.Wait()
+
+		state := config.State()
+		if state.Status != JobStatusExited {
+			t.Fatalf("unexpected job state. expected: EXITED, got: %s", state.Status)
+		}
+		if state.EndTime.Before(state.StartTime) {
+			t.Fatalf("unexpected job times. started: %s, ended: %s", state.StartTime, state.EndTime)
+		}
+	}
+}
+
+func TestCommandFailedToStart(t *testing.T) {
+	config := JobConfig{
+		Command:    "/does/not/exist",
+		CgroupName: "",
+	}
+
+	if err := config.Run(); err == nil {
+		t.Fatal("expected job to fail to start")
+	}
+	config.Wait()
+
+	state := config.State()
+	if state.Status != JobStatusFailedToStart || state.StartError == "" {
+		t.Fatalf("unexpected job state. expected: FAILED_TO_START with an error, got: %s with %q", state.Status, state.StartError)
+	}
+}
+
+func TestCommandCredential(t *testing.T) {
+	config := JobConfig{
+		Command:    "sh",
+		Args:       []string{"-c", "id -u; id -G"},
+		Namespaces: []string{},
+		Credential: &syscall.Credential{Uid: 65534, Gid: 65534, Groups: []uint32{65533}},
+	}
+
+	if err := config.Run(); err != nil {
+		t.Fatal(err)
+	}
+	config.Wait()
+
+	output, _, _ := config.Output.Read(0)
+	if fields := strings.Fields(string(output)); len(fields) != 3 || fields[0] != "65534" || fields[2] != "65533" {
+		t.Fatalf("unexpected job output. expected: 65534 and groups 65534 65533, got: %s", output)
+	}
 }
 
 // TODO: Add test for cgroup placement
 // TODO: Add test for namespace placement
+
+// procTestEnv is set when the test binary is run as the wrapper of a job by
+// TestCommandProc.
+const procTestEnv = "RJOB_TEST_PROC_WRAPPER"
+
+func TestCommandProc(t *testing.T) {
+	if os.Getenv(procTestEnv) != "" {
+		// Run in new PID and mount namespaces, like rjob's reexec operation.
+		wrapped := JobConfig{
+			Command:    "sh",
+			Args:       []string{"-c", "readlink /proc/self; ls -d /proc/[0-9]* | wc -l"},
+			Namespaces: []string{},
+			MountProc:  true,
+			Stdout:     os.Stdout,
+		}
+		if err := wrapped.Run(); err != nil {
+			t.Fatal(err)
+		}
+		wrapped.Wait()
+		return
+	}
+
+	config := JobConfig{
+		Command:    os.Args[0],
+		Args:       []string{"-test.run=^TestCommandProc$"},
+		Namespaces: []string{NamespacePid, NamespaceMount},
+	}
+	os.Setenv(procTestEnv, "1")
+	err := config.Run()
+	os.Unsetenv(procTestEnv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Wait()
+
+	// The job only sees the wrapper, the shell, and the processes the shell
+	// started, with the wrapper as PID 1.
+	output, _, _ := config.Output.Read(0)
+	fields := strings.Fields(string(output))
+	if len(fields) < 2 {
+		t.Fatalf("unexpected job output: %s", output)
+	}
+	if pid, err := strconv.Atoi(fields[0]); err != nil || pid > 10 {
+		t.Errorf("expected /proc/self to be a PID of the job's namespace, got: %s", fields[0])
+	}
+	if count, err := strconv.Atoi(fields[1]); err != nil || count > 5 {
+		t.Errorf("expected /proc to only list the job's processes, got: %s", fields[1])
+	}
+
+	hostProc, err := os.Readlink("/proc/self")
+	if err != nil || hostProc != strconv.Itoa(os.Getpid()) {
+		t.Errorf("expected the host's /proc to be left unchanged, got: %s (%v)", hostProc, err)
+	}
+}
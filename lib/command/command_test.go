@@ -0,0 +1,107 @@
+// +build unit
+
+package command
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/bill-rich/rjob/lib/rootfs"
+)
+
+func TestParseSignal(t *testing.T) {
+	testCases := []struct {
+		name           string
+		signal         string
+		expectedResult syscall.Signal
+		expectedError  bool
+	}{
+		{name: "FullName", signal: "SIGTERM", expectedResult: syscall.SIGTERM},
+		{name: "ShortName", signal: "hup", expectedResult: syscall.SIGHUP},
+		{name: "Number", signal: "9", expectedResult: syscall.SIGKILL},
+		{name: "UnknownName", signal: "SIGFOO", expectedError: true},
+		{name: "NumberUnderMin", signal: "0", expectedError: true},
+		{name: "NumberOverMax", signal: "65", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := ParseSignal(tcase.signal)
+		switch {
+		case tcase.expectedError && err == nil:
+			t.Errorf("test %s: error expected, but was not received", tcase.name)
+		case !tcase.expectedError && err != nil:
+			t.Errorf("test %s: received error: %s", tcase.name, err)
+		case result != tcase.expectedResult:
+			t.Errorf("test %s: expected: %d, got: %d", tcase.name, tcase.expectedResult, result)
+		}
+	}
+}
+
+func TestCloneflags(t *testing.T) {
+	flags, err := cloneflags([]string{NamespacePid, NamespaceNet, NamespaceUts})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := uintptr(syscall.CLONE_NEWPID | syscall.CLONE_NEWNET | syscall.CLONE_NEWUTS)
+	if flags != expected {
+		t.Errorf("expected: %x, got: %x", expected, flags)
+	}
+
+	if flags, err := cloneflags([]string{}); err != nil || flags != 0 {
+		t.Errorf("expected no flags, got: %x (%v)", flags, err)
+	}
+	if _, err := cloneflags([]string{"time"}); err == nil {
+		t.Errorf("error expected for unknown namespace, but was not received")
+	}
+}
+
+func TestJobNamespaces(t *testing.T) {
+	job := JobConfig{}
+	if len(job.namespaces()) != len(DefaultNamespaces) {
+		t.Errorf("expected default namespaces, got: %v", job.namespaces())
+	}
+	job.Namespaces = []string{}
+	if len(job.namespaces()) != 0 {
+		t.Errorf("expected no namespaces, got: %v", job.namespaces())
+	}
+
+	without := withoutNamespace([]string{NamespacePid, NamespaceCgroup}, NamespaceCgroup)
+	if len(without) != 1 || without[0] != NamespacePid {
+		t.Errorf("expected: [pid], got: %v", without)
+	}
+}
+
+func TestJobNetwork(t *testing.T) {
+	job := JobConfig{}
+	if job.network() != "none" {
+		t.Errorf("expected none in a net namespace, got: %s", job.network())
+	}
+	job.Namespaces = []string{NamespacePid}
+	if job.network() != "host" {
+		t.Errorf("expected host without a net namespace, got: %s", job.network())
+	}
+	job.Network = "bridge"
+	if job.network() != "bridge" {
+		t.Errorf("expected bridge, got: %s", job.network())
+	}
+}
+
+func TestReexecArgs(t *testing.T) {
+	job := JobConfig{
+		Namespaces: []string{NamespacePid, NamespaceMount},
+		Rootfs: &rootfs.Config{
+			Layers: []string{"/images/base", "/images/top"},
+			Mounts: []rootfs.Mount{{Source: "/data", Target: "/data", ReadOnly: true}},
+		},
+	}
+	expected := "reexec --reportfd 3 --rootfs /images/base /images/top --mount /data:/data:ro --"
+	if args := strings.Join(job.reexecArgs(), " "); args != expected {
+		t.Errorf("expected: %s, got: %s", expected, args)
+	}
+
+	job.Rootfs = nil
+	expected = "reexec --reportfd 3 --proc --"
+	if args := strings.Join(job.reexecArgs(), " "); args != expected {
+		t.Errorf("expected: %s, got: %s", expected, args)
+	}
+}
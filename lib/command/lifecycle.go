@@ -0,0 +1,154 @@
+package command
+
+import (
+	"os"
+	"syscall"
+	"time"
+
+	"github.com/bill-rich/rjob/lib/cgroup"
+	"golang.org/x/sys/unix"
+)
+
+// The lifecycle of a job starts as PENDING. Once the process has been started
+// the job is RUNNING, otherwise it ends up as FAILED_TO_START. Running jobs end
+// up as EXITED when the process exits on its own, or KILLED when it was
+// terminated by a signal.
+const (
+	JobStatusPending       = "PENDING"
+	JobStatusRunning       = "RUNNING"
+	JobStatusExited        = "EXITED"
+	JobStatusKilled        = "KILLED"
+	JobStatusFailedToStart = "FAILED_TO_START"
+	// JobStatusLost is used for jobs that were still running when the rjob
+	// server stopped. Their outcome is unknown.
+	JobStatusLost = "LOST"
+)
+
+// JobState describes where a job is in its lifecycle.
+type JobState struct {
+	Status string
+	// ExitCode is 128 plus the signal number for jobs killed by a signal.
+	ExitCode   int
+	Signal     string
+	StartTime  time.Time
+	EndTime    time.Time
+	StartError string
+	// Usage is the last resource usage read from the job's cgroup.
+	Usage cgroup.Usage
+}
+
+// closedDone is returned by Done for jobs that were never run.
+var closedDone = func() chan struct{} {
+	done := make(chan struct{})
+	close(done)
+	return done
+}()
+
+// State returns a snapshot of the job's state.
+func (job *JobConfig) State() JobState {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	return job.JobState
+}
+
+// IsRunning returns true if the job is still running.
+func (job *JobConfig) IsRunning() bool {
+	return job.State().Status == JobStatusRunning
+}
+
+// Done returns a channel that is closed once the job has ended.
+func (job *JobConfig) Done() <-chan struct{} {
+	if job.done == nil {
+		return closedDone
+	}
+	return job.done
+}
+
+// Wait blocks until the job has ended.
+func (job *JobConfig) Wait() {
+	<-job.Done()
+}
+
+// setRunning marks the job as running, unless it has already moved on from
+// PENDING.
+func (job *JobConfig) setRunning() {
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	if job.Status != JobStatusPending {
+		return
+	}
+	job.Status = JobStatusRunning
+	job.StartTime = time.Now()
+}
+
+// setFailedToStart marks the job as failed to start. The job is ended once any
+// process that was started has exited.
+func (job *JobConfig) setFailedToStart(err error) {
+	job.mu.Lock()
+	job.Status = JobStatusFailedToStart
+	job.StartError = err.Error()
+	job.EndTime = time.Now()
+	job.mu.Unlock()
+
+	if job.Cmd == nil || job.Cmd.Process == nil {
+		job.end()
+	}
+}
+
+// setExited records how the job's process ended, and marks the job as ended.
+func (job *JobConfig) setExited(exitCode int, signal string) {
+	job.mu.Lock()
+	if job.Status != JobStatusFailedToStart {
+		job.Status = JobStatusExited
+		if signal != "" {
+			job.Status = JobStatusKilled
+		}
+		job.ExitCode = exitCode
+		job.Signal = signal
+		job.EndTime = time.Now()
+	}
+	job.mu.Unlock()
+
+	job.end()
+}
+
+// end marks the job as ended. Its output is closed as well, since output that
+// was not captured, such as stderr of jobs with a tty, would otherwise keep
+// readers waiting.
+func (job *JobConfig) end() {
+	for _, buf := range []*OutputBuffer{job.Output, job.ErrOutput, job.Records} {
+		if buf != nil {
+			buf.Close()
+		}
+	}
+	close(job.done)
+}
+
+// UpdateUsage reads the resources used by the job from its cgroup. The last
+// usage read is kept once the cgroup has been deleted.
+func (job *JobConfig) UpdateUsage() error {
+	if job.CgroupConfig == nil {
+		return nil
+	}
+	if _, err := os.Stat(job.CgroupConfig.Path); os.IsNotExist(err) {
+		return nil
+	}
+
+	usage, err := job.CgroupConfig.Usage()
+	if err != nil {
+		return err
+	}
+	job.mu.Lock()
+	defer job.mu.Unlock()
+	job.Usage = usage
+	return nil
+}
+
+// exitStatus returns the exit code and terminating signal from the wait status
+// of a process.
+func exitStatus(waitStatus syscall.WaitStatus) (int, string) {
+	if waitStatus.Signaled() {
+		return 128 + int(waitStatus.Signal()), unix.SignalName(waitStatus.Signal())
+	}
+	return waitStatus.ExitStatus(), ""
+}
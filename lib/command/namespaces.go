@@ -0,0 +1,93 @@
+package command
+
+import (
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Namespaces that jobs can be run in.
+const (
+	NamespacePid    = "pid"
+	NamespaceMount  = "mount"
+	NamespaceNet    = "net"
+	NamespaceUts    = "uts"
+	NamespaceIpc    = "ipc"
+	NamespaceUser   = "user"
+	NamespaceCgroup = "cgroup"
+)
+
+// DefaultNamespaces are used for jobs that do not select their namespaces.
+var DefaultNamespaces = []string{NamespacePid, NamespaceMount, NamespaceNet}
+
+var namespaceFlags = map[string]uintptr{
+	NamespacePid:    syscall.CLONE_NEWPID,
+	NamespaceMount:  syscall.CLONE_NEWNS,
+	NamespaceNet:    syscall.CLONE_NEWNET,
+	NamespaceUts:    syscall.CLONE_NEWUTS,
+	NamespaceIpc:    syscall.CLONE_NEWIPC,
+	NamespaceUser:   syscall.CLONE_NEWUSER,
+	NamespaceCgroup: syscall.CLONE_NEWCGROUP,
+}
+
+// ValidateNamespaces returns an error if any of the namespaces is unknown.
+func ValidateNamespaces(namespaces []string) error {
+	_, err := cloneflags(namespaces)
+	return err
+}
+
+// HasNamespace returns true if namespace is one of namespaces.
+func HasNamespace(namespaces []string, namespace string) bool {
+	for _, ns := range namespaces {
+		if ns == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// namespaces returns the namespaces the job is run in.
+func (job *JobConfig) namespaces() []string {
+	if job.Namespaces == nil {
+		return DefaultNamespaces
+	}
+	return job.Namespaces
+}
+
+// withoutNamespace returns namespaces without namespace.
+func withoutNamespace(namespaces []string, namespace string) []string {
+	without := []string{}
+	for _, ns := range namespaces {
+		if ns != namespace {
+			without = append(without, ns)
+		}
+	}
+	return without
+}
+
+// cloneflags returns the clone flags creating the namespaces.
+func cloneflags(namespaces []string) (uintptr, error) {
+	var flags uintptr
+	for _, ns := range namespaces {
+		flag, ok := namespaceFlags[ns]
+		if !ok {
+			return 0, fmt.Errorf("unknown namespace: %s", ns)
+		}
+		flags |= flag
+	}
+	return flags, nil
+}
+
+// mountProc mounts a new procfs on /proc, showing the processes of the current
+// PID namespace. Mount propagation is made private first, so that neither this
+// mount nor later ones reach the host.
+func mountProc() error {
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("unable to make mounts private: %s", err)
+	}
+	if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("unable to mount /proc: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/bill-rich/rjob/lib/network"
+)
+
+// NetworkFd is the file descriptor on which rjob's reexec operation receives
+// the network configuration of the job from the server.
+const NetworkFd = 4
+
+// network returns the network mode of the job. Jobs default to
+// network.ModeNone in a net namespace, and network.ModeHost otherwise.
+func (job *JobConfig) network() string {
+	if job.Network != "" {
+		return job.Network
+	}
+	if HasNamespace(job.namespaces(), NamespaceNet) {
+		return network.ModeNone
+	}
+	return network.ModeHost
+}
+
+// openNetworkPipe creates the pipe used to send the network configuration to
+// the reexec wrapper. The read end is passed to the wrapper as NetworkFd.
+func (job *JobConfig) openNetworkPipe() (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create network pipe: %s", err)
+	}
+	job.Cmd.ExtraFiles = append(job.Cmd.ExtraFiles, r)
+	return w, nil
+}
+
+// sendNetworkConfig sets up the network of the wrapper's net namespace from the
+// host, and sends the configuration the wrapper applies inside of it. The pipe
+// is closed without a configuration if the setup fails.
+func (job *JobConfig) sendNetworkConfig(w *os.File) error {
+	defer w.Close()
+	cfg := network.Config{Mode: job.network()}
+	if job.SetupNetwork != nil {
+		var err error
+		if cfg, err = job.SetupNetwork(job.Cmd.Process.Pid); err != nil {
+			return fmt.Errorf("unable to set up network: %s", err)
+		}
+	}
+	return json.NewEncoder(w).Encode(cfg)
+}
+
+// ReadNetworkConfig reads the network configuration sent by the server to rjob's
+// reexec operation.
+func ReadNetworkConfig(r *os.File) (network.Config, error) {
+	defer r.Close()
+	cfg := network.Config{}
+	if err := json.NewDecoder(r).Decode(&cfg); err != nil {
+		return cfg, fmt.Errorf("network configuration was not received")
+	}
+	return cfg, nil
+}
@@ -1,29 +1,319 @@
 package command
 
+import (
+	"context"
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// readLimit is the most output returned by a single Read, which keeps chunks
+// well below the message size limit of gRPC.
+const readLimit = 1 << 20
+
+// tailChunk is how much output TailOffset searches for lines at a time.
+const tailChunk = 32 * 1024
+
+// OutputLimits bound how much output of a job is kept.
+type OutputLimits struct {
+	// Memory is the most output kept in memory. Older output is only kept
+	// in the log of the OutputBuffer, or dropped when there is none. Zero
+	// keeps all output in memory.
+	Memory int64
+	// Retain is the most output kept at all. Only the last Retain bytes of
+	// output can be read. Zero keeps all output.
+	Retain int64
+}
+
+// OutputLog is where an OutputBuffer spills its output, so that output no
+// longer held in memory can be read back.
+type OutputLog interface {
+	io.Writer
+	io.ReaderAt
+	// Size returns the number of bytes in the log.
+	Size() (int64, error)
+	// Discard removes the first n bytes of the log. Later bytes keep their
+	// order, but move to the front of the log.
+	Discard(n int64) error
+	// Close ends writing to the log. The log can still be read.
+	Close() error
+}
+
+// OutputBuffer holds the output of a stream. Output is addressed by its offset
+// from the start of the stream, which does not change when older output is
+// dropped. It is safe to write and read output from different goroutines.
 type OutputBuffer struct {
-	data []byte
+	Limits OutputLimits
+	// AfterDiscard is called once older output has been discarded from the
+	// log, which moves LogStart. It is called from Write, without holding
+	// the lock of the buffer.
+	AfterDiscard func()
+
+	mu sync.Mutex
+	// changed is closed to wake up readers waiting in Next, once output is
+	// written or the buffer is closed.
+	changed chan struct{}
+	closed  bool
+	// data is the most recent output, starting at offset dataStart.
+	data      []byte
+	dataStart int64
+	// log holds all retained output, starting at offset logStart and ending
+	// before offset logEnd. Once writing to the log fails, no more output is
+	// written to it, so logEnd stays behind size.
+	log      OutputLog
+	logStart int64
+	logEnd   int64
+	// size is the offset following the last byte of output.
+	size int64
+}
+
+// NewOutputBuffer returns an OutputBuffer spilling its output to log, which may
+// be nil. The log already holds the output from offset start on, which is read
+// back from the log instead of being loaded into memory.
+func NewOutputBuffer(limits OutputLimits, log OutputLog, start int64) (*OutputBuffer, error) {
+	b := &OutputBuffer{Limits: limits, log: log, logStart: start, logEnd: start, dataStart: start, size: start}
+	if log != nil {
+		size, err := log.Size()
+		if err != nil {
+			return nil, err
+		}
+		b.logEnd += size
+		b.dataStart += size
+		b.size += size
+	}
+	return b, nil
 }
 
 // Write adds new bytes to the OutputBuffer.
 func (b *OutputBuffer) Write(newData []byte) {
+	if len(newData) == 0 {
+		return
+	}
+	if b.write(newData) && b.AfterDiscard != nil {
+		b.AfterDiscard()
+	}
+}
+
+// write implements Write, and returns true if older output was discarded from
+// the log.
+func (b *OutputBuffer) write(newData []byte) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	defer b.notify()
+
+	// Output written to the log after a failed write would be read back from
+	// the wrong offsets, so the log is only written while it is complete.
+	if b.log != nil && b.logEnd == b.size {
+		n, err := b.log.Write(newData)
+		b.logEnd += int64(n)
+		if err != nil {
+			log.Warnf("Unable to write output to log, no longer writing to it: %s", err)
+		}
+	}
 	b.data = append(b.data, newData...)
+	b.size += int64(len(newData))
+
+	// Output is dropped in bulk, so that it is not copied on every write. Half
+	// of the memory limit is kept once it is exceeded. Output that is not
+	// retained is dropped once twice the retention limit is held, as in the
+	// log.
+	keep := int64(-1)
+	if b.Limits.Memory > 0 && int64(len(b.data)) > b.Limits.Memory {
+		keep = b.Limits.Memory / 2
+	}
+	if retain := b.Limits.Retain; retain > 0 && int64(len(b.data)) > 2*retain && (keep < 0 || keep > retain) {
+		keep = retain
+	}
+	if keep >= 0 {
+		b.data = append([]byte{}, b.data[int64(len(b.data))-keep:]...)
+		b.dataStart = b.size - keep
+	}
+
+	if b.log != nil && b.Limits.Retain > 0 && b.logEnd-b.logStart > 2*b.Limits.Retain {
+		discard := b.logEnd - b.logStart - b.Limits.Retain
+		if err := b.log.Discard(discard); err != nil {
+			log.Warnf("Unable to discard old output from log: %s", err)
+			return false
+		}
+		b.logStart += discard
+		return true
+	}
+	return false
+}
+
+// start returns the offset of the oldest output that can be read.
+func (b *OutputBuffer) start() int64 {
+	start := b.dataStart
+	if b.log != nil {
+		start = b.logStart
+	}
+	if b.Limits.Retain > 0 && b.size-b.Limits.Retain > start {
+		start = b.size - b.Limits.Retain
+	}
+	return start
 }
 
-// Read returns and new output beyond 'current' and the current length of the
-// output.
-func (b *OutputBuffer) Read(current int) (string, int) {
-	size := len(b.data)
-	if b.HasNew(current) {
-		return string(b.data[current:]), size
+// notify wakes up the readers waiting for output. The caller must hold mu.
+func (b *OutputBuffer) notify() {
+	if b.changed != nil {
+		close(b.changed)
+		b.changed = nil
 	}
-	return "", size
+}
+
+// Close marks the end of the output, and ends writing to the log of the
+// OutputBuffer, if there is one. Readers waiting for output are woken up.
+// Closing an OutputBuffer more than once has no effect.
+func (b *OutputBuffer) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.notify()
+	if b.log == nil {
+		return nil
+	}
+	return b.log.Close()
+}
+
+// Read returns new output beyond offset, along with the offset following the
+// returned output. At most readLimit bytes are returned, so the output may need
+// to be read several times to catch up.
+//
+// Output that is no longer retained is skipped, and the number of bytes
+// skipped before the returned output is reported. Output that cannot be read
+// back from the log is reported as skipped as well.
+func (b *OutputBuffer) Read(offset int64) ([]byte, int64, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.read(offset, readLimit)
+}
+
+// Next returns new output beyond offset in the same way as Read, but waits for
+// output to be written when there is none yet. Once the buffer is closed and
+// all output has been read, io.EOF is returned. Waiting stops with the error of
+// ctx when ctx is done.
+func (b *OutputBuffer) Next(ctx context.Context, offset int64) ([]byte, int64, int64, error) {
+	for {
+		b.mu.Lock()
+		if b.size > offset {
+			data, next, skipped := b.read(offset, readLimit)
+			b.mu.Unlock()
+			return data, next, skipped, nil
+		}
+		if b.closed {
+			b.mu.Unlock()
+			return nil, offset, 0, io.EOF
+		}
+		if b.changed == nil {
+			b.changed = make(chan struct{})
+		}
+		changed := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, offset, 0, ctx.Err()
+		}
+	}
+}
+
+// read implements Read, returning at most limit bytes. The caller must hold mu.
+func (b *OutputBuffer) read(offset, limit int64) ([]byte, int64, int64) {
+	var skipped int64
+	if start := b.start(); offset < start {
+		skipped, offset = start-offset, start
+	}
+	if offset >= b.size {
+		return nil, b.size, skipped
+	}
+
+	if offset < b.dataStart {
+		if b.log != nil && offset < b.logEnd {
+			data := make([]byte, min64(min64(b.dataStart, b.logEnd)-offset, limit))
+			n, err := b.log.ReadAt(data, offset-b.logStart)
+			if n > 0 {
+				return data[:n], offset + int64(n), skipped
+			}
+			log.Warnf("Unable to read output from log: %s", err)
+		}
+		skipped, offset = skipped+b.dataStart-offset, b.dataStart
+	}
+
+	end := min64(b.size, offset+limit)
+	// The returned output must not change along with the buffer.
+	data := append([]byte{}, b.data[offset-b.dataStart:end-b.dataStart]...)
+	return data, end, skipped
+}
+
+// TailOffset returns the offset of the last n lines of output, or of the oldest
+// retained output when fewer lines are retained. A newline ending the output
+// does not start another line.
+func (b *OutputBuffer) TailOffset(n int) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	start, end := b.start(), b.size
+	lines := 0
+	for end > start {
+		// Output is searched from the end, one chunk at a time.
+		from := end - tailChunk
+		if from < start {
+			from = start
+		}
+		chunk := []byte{}
+		for offset := from; offset < end; {
+			data, next, skipped := b.read(offset, end-offset)
+			if skipped > 0 {
+				// Output that cannot be read back has no lines.
+				return offset
+			}
+			chunk, offset = append(chunk, data...), next
+		}
+
+		for i := len(chunk) - 1; i >= 0; i-- {
+			if chunk[i] != '\n' || from+int64(i) == b.size-1 {
+				continue
+			}
+			if lines++; lines == n {
+				return from + int64(i) + 1
+			}
+		}
+		end = from
+	}
+	return start
+}
+
+// Size returns the offset following the last byte of output.
+func (b *OutputBuffer) Size() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
 }
 
 // HasNew is used to determine if any new output has been added beyond the
-// "current" index.
-func (b *OutputBuffer) HasNew(current int) bool {
-	if len(b.data) > current {
-		return true
+// "current" offset.
+func (b *OutputBuffer) HasNew(current int64) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size > current
+}
+
+// LogStart returns the offset of the first byte held by the log of the
+// OutputBuffer.
+func (b *OutputBuffer) LogStart() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.logStart
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
 	}
-	return false
+	return b
 }
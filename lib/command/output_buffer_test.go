@@ -0,0 +1,314 @@
+// +build unit
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// memoryLog is an OutputLog kept in memory.
+type memoryLog struct {
+	data []byte
+}
+
+func (l *memoryLog) Write(data []byte) (int, error) {
+	l.data = append(l.data, data...)
+	return len(data), nil
+}
+
+func (l *memoryLog) ReadAt(data []byte, offset int64) (int, error) {
+	if offset >= int64(len(l.data)) {
+		return 0, io.EOF
+	}
+	return copy(data, l.data[offset:]), nil
+}
+
+func (l *memoryLog) Size() (int64, error) {
+	return int64(len(l.data)), nil
+}
+
+func (l *memoryLog) Discard(n int64) error {
+	l.data = append([]byte{}, l.data[n:]...)
+	return nil
+}
+
+func (l *memoryLog) Close() error {
+	return nil
+}
+
+// fullLog is a memoryLog that fails to write beyond limit bytes.
+type fullLog struct {
+	memoryLog
+	limit int
+}
+
+func (l *fullLog) Write(data []byte) (int, error) {
+	if room := l.limit - len(l.data); len(data) > room {
+		l.memoryLog.Write(data[:room])
+		return room, io.ErrShortWrite
+	}
+	return l.memoryLog.Write(data)
+}
+
+// readAll reads all output from offset on, along with the number of bytes
+// skipped.
+func readAll(b *OutputBuffer, offset int64) (string, int64) {
+	output, skipped := "", int64(0)
+	for b.HasNew(offset) {
+		var data []byte
+		var n int64
+		data, offset, n = b.Read(offset)
+		output += string(data)
+		skipped += n
+	}
+	return output, skipped
+}
+
+func TestOutputBufferUnlimited(t *testing.T) {
+	b := &OutputBuffer{}
+	b.Write([]byte("first "))
+	b.Write([]byte("second"))
+
+	data, next, skipped := b.Read(0)
+	if string(data) != "first second" || next != 12 || skipped != 0 {
+		t.Errorf("unexpected read. got: %q, %d, %d", data, next, skipped)
+	}
+	data, next, skipped = b.Read(6)
+	if string(data) != "second" || next != 12 || skipped != 0 {
+		t.Errorf("unexpected read. got: %q, %d, %d", data, next, skipped)
+	}
+	if b.HasNew(12) {
+		t.Errorf("expected no new output")
+	}
+}
+
+func TestOutputBufferBinary(t *testing.T) {
+	binary := []byte{0xff, 0x00, 0xfe, '\n', 0xc3, 0x28}
+	b := &OutputBuffer{}
+	b.Write(binary)
+
+	data, _, _ := b.Read(0)
+	if !bytes.Equal(data, binary) {
+		t.Errorf("unexpected output. expected: %v, got: %v", binary, data)
+	}
+	// Later output must not change output that was already read.
+	b.Write([]byte("more"))
+	if !bytes.Equal(data, binary) {
+		t.Errorf("output changed after reading. expected: %v, got: %v", binary, data)
+	}
+}
+
+func TestOutputBufferSpill(t *testing.T) {
+	outputLog := &memoryLog{}
+	b, err := NewOutputBuffer(OutputLimits{Memory: 8}, outputLog, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		b.Write([]byte("0123456789"))
+	}
+	if len(b.data) > 8 {
+		t.Errorf("expected at most 8 bytes in memory, got: %d", len(b.data))
+	}
+
+	// Reads cross from the log into memory.
+	output, skipped := readAll(b, 5)
+	if output != strings.Repeat("0123456789", 10)[5:] || skipped != 0 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+}
+
+func TestOutputBufferDropped(t *testing.T) {
+	b := &OutputBuffer{Limits: OutputLimits{Memory: 8}}
+	for i := 0; i < 10; i++ {
+		b.Write([]byte("0123456789"))
+	}
+
+	output, skipped := readAll(b, 0)
+	if int64(len(output))+skipped != 100 || !strings.HasSuffix(output, "789") || len(output) > 8 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+}
+
+func TestOutputBufferRetain(t *testing.T) {
+	outputLog := &memoryLog{}
+	b, err := NewOutputBuffer(OutputLimits{Memory: 4, Retain: 20}, outputLog, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		b.Write([]byte("0123456789"))
+	}
+	if len(outputLog.data) > 40 {
+		t.Errorf("expected at most 40 bytes in the log, got: %d", len(outputLog.data))
+	}
+
+	output, skipped := readAll(b, 0)
+	if output != "01234567890123456789" || skipped != 80 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+	output, skipped = readAll(b, 95)
+	if output != "56789" || skipped != 0 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+}
+
+func TestOutputBufferRetainMemory(t *testing.T) {
+	b := &OutputBuffer{Limits: OutputLimits{Retain: 20}}
+	for i := 0; i < 30; i++ {
+		b.Write([]byte("0123456"))
+
+		// All retained output stays readable without a log.
+		output, _ := readAll(b, 0)
+		if len(output) < 20 && len(output) != 7*(i+1) {
+			t.Fatalf("expected at least 20 bytes of output, got: %q", output)
+		}
+	}
+
+	output, skipped := readAll(b, 0)
+	if output != strings.Repeat("0123456", 30)[190:] || skipped != 190 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+}
+
+func TestOutputBufferAfterDiscard(t *testing.T) {
+	outputLog := &memoryLog{}
+	b, err := NewOutputBuffer(OutputLimits{Retain: 20}, outputLog, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	starts := []int64{}
+	b.AfterDiscard = func() {
+		starts = append(starts, b.LogStart())
+	}
+	for i := 0; i < 10; i++ {
+		b.Write([]byte("0123456789"))
+	}
+
+	if len(starts) == 0 || starts[len(starts)-1] != b.LogStart() || b.LogStart() == 0 {
+		t.Errorf("expected every discard to be reported, got: %v, log starts at %d", starts, b.LogStart())
+	}
+}
+
+func TestOutputBufferLogFailure(t *testing.T) {
+	outputLog := &fullLog{limit: 15}
+	b, err := NewOutputBuffer(OutputLimits{Memory: 8}, outputLog, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		b.Write([]byte("0123456789"))
+	}
+	if len(outputLog.data) != 15 {
+		t.Errorf("expected no more output written after the log failed, got: %q", outputLog.data)
+	}
+
+	// Output between the end of the log and memory is lost.
+	output, skipped := readAll(b, 0)
+	if output != "012345678901234"+"6789" || skipped != 11 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+}
+
+func TestOutputBufferRestore(t *testing.T) {
+	outputLog := &memoryLog{data: []byte("saved output")}
+	b, err := NewOutputBuffer(OutputLimits{}, outputLog, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, skipped := readAll(b, 0)
+	if output != "saved output" || skipped != 100 {
+		t.Errorf("unexpected output. got: %q, skipped %d", output, skipped)
+	}
+	if b.LogStart() != 100 {
+		t.Errorf("expected the log to start at 100, got: %d", b.LogStart())
+	}
+}
+
+func TestOutputBufferNext(t *testing.T) {
+	b := &OutputBuffer{}
+	go func() {
+		for _, chunk := range []string{"first ", "second"} {
+			time.Sleep(10 * time.Millisecond)
+			b.Write([]byte(chunk))
+		}
+		b.Close()
+	}()
+
+	output, offset := "", int64(0)
+	for {
+		data, next, _, err := b.Next(context.Background(), offset)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		output, offset = output+string(data), next
+	}
+	if output != "first second" {
+		t.Errorf("unexpected output. expected: first second, got: %q", output)
+	}
+
+	// Closing again has no effect, and the end of the output is still seen.
+	b.Close()
+	if _, _, _, err := b.Next(context.Background(), offset); err != io.EOF {
+		t.Errorf("expected io.EOF, got: %v", err)
+	}
+}
+
+func TestOutputBufferNextCanceled(t *testing.T) {
+	b := &OutputBuffer{}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, _, _, err := b.Next(ctx, 0); err != context.DeadlineExceeded {
+		t.Errorf("expected context.DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestOutputBufferTailOffset(t *testing.T) {
+	testCases := []struct {
+		name     string
+		output   string
+		lines    int
+		expected int64
+	}{
+		{name: "TrailingNewline", output: "a\nb\nc\n", lines: 2, expected: 2},
+		{name: "NoTrailingNewline", output: "a\nb\nc", lines: 2, expected: 2},
+		{name: "FewerLines", output: "a\nb\n", lines: 5, expected: 0},
+		{name: "Empty", output: "", lines: 1, expected: 0},
+	}
+	for _, tcase := range testCases {
+		b := &OutputBuffer{}
+		b.Write([]byte(tcase.output))
+		if offset := b.TailOffset(tcase.lines); offset != tcase.expected {
+			t.Errorf("%s: expected offset %d, got: %d", tcase.name, tcase.expected, offset)
+		}
+	}
+}
+
+func TestOutputBufferTailOffsetLog(t *testing.T) {
+	// Lines span several search chunks, in the log as well as in memory.
+	line := strings.Repeat("x", 1000) + "\n"
+	b, err := NewOutputBuffer(OutputLimits{Memory: 4096, Retain: 100 * 1001}, &memoryLog{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		b.Write([]byte(line))
+	}
+
+	if offset := b.TailOffset(50); offset != 150*1001 {
+		t.Errorf("expected offset %d, got: %d", 150*1001, offset)
+	}
+	// Only the retained lines can be returned.
+	if offset := b.TailOffset(150); offset != 100*1001 {
+		t.Errorf("expected offset %d, got: %d", 100*1001, offset)
+	}
+}
@@ -0,0 +1,116 @@
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// The streams of output a record can come from.
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// maxRecordLine is the longest line kept in a single record. Longer lines are
+// split over several records.
+const maxRecordLine = 64 * 1024
+
+// OutputRecord is a line of output of a job. Records of both streams share one
+// sequence, so their order is kept.
+type OutputRecord struct {
+	Sequence uint64 `json:"seq"`
+	// Time is when the first byte of the line was received.
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"`
+	// Line is the line without its newline.
+	Line []byte `json:"line"`
+}
+
+// ParseRecords parses the records in data, one JSON record per line. Lines that
+// are not records, such as the remains of a record that was partly dropped, are
+// skipped.
+func ParseRecords(data []byte) []OutputRecord {
+	records := []OutputRecord{}
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		record := OutputRecord{}
+		if len(line) == 0 || json.Unmarshal(line, &record) != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// recorder frames the output of a job into records, which are written to an
+// OutputBuffer as lines of JSON.
+type recorder struct {
+	mu       sync.Mutex
+	buf      *OutputBuffer
+	sequence uint64
+}
+
+// record writes a record of a line received at time t.
+func (r *recorder) record(stream string, t time.Time, line []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.Marshal(OutputRecord{Sequence: r.sequence, Time: t, Stream: stream, Line: line})
+	if err != nil {
+		log.Warnf("Unable to encode output record: %s", err)
+		return
+	}
+	r.sequence++
+	r.buf.Write(append(data, '\n'))
+}
+
+// lineRecorder collects the output of one stream into lines for a recorder.
+type lineRecorder struct {
+	recorder *recorder
+	stream   string
+	// line is the start of a line whose newline was not received yet, and
+	// started is when its first byte was received.
+	line    []byte
+	started time.Time
+}
+
+// Write records every line completed by data.
+func (l *lineRecorder) Write(data []byte) {
+	now := time.Now()
+	for len(data) > 0 {
+		if len(l.line) == 0 {
+			l.started = now
+		}
+		i := bytes.IndexByte(data, '\n')
+		if room := maxRecordLine - len(l.line); i < 0 || i > room {
+			// The line is recorded in parts once it grows too long.
+			if room > len(data) {
+				room = len(data)
+			}
+			l.line = append(l.line, data[:room]...)
+			data = data[room:]
+			if len(l.line) == maxRecordLine {
+				l.flush()
+			}
+			continue
+		}
+		l.line = append(l.line, data[:i]...)
+		l.flush()
+		data = data[i+1:]
+	}
+}
+
+// flush records the collected line, which may be empty.
+func (l *lineRecorder) flush() {
+	l.recorder.record(l.stream, l.started, l.line)
+	l.line = nil
+}
+
+// Close records the last line, if it did not end with a newline.
+func (l *lineRecorder) Close() {
+	if len(l.line) > 0 {
+		l.flush()
+	}
+}
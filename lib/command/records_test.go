@@ -0,0 +1,66 @@
+// +build unit
+
+package command
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineRecorder(t *testing.T) {
+	buf := &OutputBuffer{}
+	r := &recorder{buf: buf}
+	stdout := &lineRecorder{recorder: r, stream: StreamStdout}
+	stderr := &lineRecorder{recorder: r, stream: StreamStderr}
+
+	stdout.Write([]byte("first\nsec"))
+	stderr.Write([]byte("error\n"))
+	stdout.Write([]byte("ond\n\nlast"))
+	stdout.Close()
+	stderr.Close()
+
+	data, _, _ := buf.Read(0)
+	records := ParseRecords(data)
+	expected := []struct {
+		stream, line string
+	}{
+		{StreamStdout, "first"},
+		{StreamStderr, "error"},
+		{StreamStdout, "second"},
+		{StreamStdout, ""},
+		{StreamStdout, "last"},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got: %+v", len(expected), records)
+	}
+	for i, record := range records {
+		if record.Sequence != uint64(i) || record.Stream != expected[i].stream || string(record.Line) != expected[i].line {
+			t.Errorf("unexpected record %d. expected: %s %q, got: %+v", i, expected[i].stream, expected[i].line, record)
+		}
+		if record.Time.IsZero() {
+			t.Errorf("record %d has no time", i)
+		}
+	}
+}
+
+func TestLineRecorderLongLine(t *testing.T) {
+	buf := &OutputBuffer{}
+	lines := &lineRecorder{recorder: &recorder{buf: buf}, stream: StreamStdout}
+	lines.Write([]byte(strings.Repeat("x", maxRecordLine+10)))
+	lines.Close()
+
+	data, _, _ := buf.Read(0)
+	records := ParseRecords(data)
+	if len(records) != 2 || len(records[0].Line) != maxRecordLine || len(records[1].Line) != 10 {
+		t.Errorf("expected the line to be split in two records, got %d records", len(records))
+	}
+}
+
+func TestParseRecords(t *testing.T) {
+	// The first line is the end of a record whose start was dropped.
+	data := `"line":"eA=="}` + "\n" + `{"seq":7,"time":"2026-01-02T03:04:05Z","stream":"stderr","line":"b2s="}` + "\n"
+	records := ParseRecords([]byte(data))
+	if len(records) != 1 || records[0].Sequence != 7 || records[0].Stream != StreamStderr || string(records[0].Line) != "ok" {
+		t.Errorf("unexpected records: %+v", records)
+	}
+}
@@ -0,0 +1,66 @@
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ReportFd is the file descriptor used by rjob's reexec operation to report
+// back to the server.
+const ReportFd = 3
+
+// report is sent from rjob's reexec operation to the server. The first report
+// tells whether the command was started, and the second how it exited. This
+// lets the server track the command itself instead of the reexec wrapper.
+type report struct {
+	Started  bool   `json:"started,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Exited   bool   `json:"exited,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+	Signal   string `json:"signal,omitempty"`
+}
+
+// sendReport writes a report to the job's report pipe, if it has one.
+func (job *JobConfig) sendReport(r report) {
+	if job.Report == nil {
+		return
+	}
+	json.NewEncoder(job.Report).Encode(r)
+}
+
+// readStartReport waits for the reexec wrapper to report whether the command
+// was started.
+func (job *JobConfig) readStartReport() error {
+	r := report{}
+	if err := job.reports.Decode(&r); err != nil {
+		return fmt.Errorf("rjob reexec exited before starting the job")
+	}
+	if !r.Started {
+		return fmt.Errorf("%s", r.Error)
+	}
+	return nil
+}
+
+// readExitReport returns the report of how the command exited, if the reexec
+// wrapper was able to send one.
+func (job *JobConfig) readExitReport() (report, bool) {
+	r := report{}
+	if err := job.reports.Decode(&r); err != nil || !r.Exited {
+		return r, false
+	}
+	return r, true
+}
+
+// openReportPipe creates the pipe used by the reexec wrapper to report back.
+// The write end is passed to the wrapper as ReportFd.
+func (job *JobConfig) openReportPipe() (*os.File, error) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create report pipe: %s", err)
+	}
+	job.reportPipe = r
+	job.reports = json.NewDecoder(r)
+	job.Cmd.ExtraFiles = append(job.Cmd.ExtraFiles, w)
+	return w, nil
+}
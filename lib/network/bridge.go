@@ -0,0 +1,287 @@
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/vishvananda/netlink"
+)
+
+// The bridge and subnet used for jobs in ModeBridge. These could be read from a
+// configuration.
+const (
+	BridgeName = "rjob0"
+	Subnet     = "10.88.0.0/16"
+)
+
+const ipForwardFile = "/proc/sys/net/ipv4/ip_forward"
+
+// Bridge connects the net namespaces of jobs to the host. Each job gets a veth
+// pair, one end of which is attached to the bridge, and an address in Subnet.
+// The bridge holds the first address of the subnet, and is the gateway of jobs.
+type Bridge struct {
+	link    netlink.Link
+	subnet  *net.IPNet
+	gateway net.IPNet
+	// nat is false when the NAT rules could not be set up. Jobs can then
+	// only reach the host, and ports cannot be forwarded.
+	nat bool
+	// forwarding is set once the rules forwarding traffic of jobs were
+	// added. They are removed by Close.
+	forwarding bool
+
+	mu        sync.Mutex
+	addresses map[string]net.IP
+	ports     map[string]string
+	forwards  map[string][]PortForward
+}
+
+// SetupBridge creates the bridge, unless it exists, and sets up NAT for the
+// subnet. Failing to set up NAT is not fatal, as jobs can still reach the host.
+func SetupBridge() (*Bridge, error) {
+	_, subnet, err := net.ParseCIDR(Subnet)
+	if err != nil {
+		return nil, err
+	}
+	bridge := &Bridge{
+		subnet:    subnet,
+		gateway:   net.IPNet{IP: nthAddress(subnet, 1), Mask: subnet.Mask},
+		addresses: map[string]net.IP{},
+		ports:     map[string]string{},
+		forwards:  map[string][]PortForward{},
+	}
+
+	link, err := netlink.LinkByName(BridgeName)
+	if err != nil {
+		link = &netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Name: BridgeName}}
+		if err := netlink.LinkAdd(link); err != nil {
+			return nil, fmt.Errorf("unable to create bridge %s: %s", BridgeName, err)
+		}
+	}
+	bridge.link = link
+	if err := netlink.AddrReplace(link, &netlink.Addr{IPNet: &bridge.gateway}); err != nil {
+		return nil, fmt.Errorf("unable to add address to bridge %s: %s", BridgeName, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return nil, fmt.Errorf("unable to bring up bridge %s: %s", BridgeName, err)
+	}
+
+	if err := ioutil.WriteFile(ipForwardFile, []byte("1"), 0644); err != nil {
+		log.Warnf("Unable to enable IP forwarding: %s", err)
+	}
+	if err := setupNat(subnet.String()); err != nil {
+		log.Warnf("Jobs using bridge networking will only reach the host: %s", err)
+	} else {
+		bridge.nat = true
+	}
+	if err := setupForwarding(); err != nil {
+		log.Warnf("Unable to allow forwarding of traffic from jobs: %s", err)
+	} else {
+		bridge.forwarding = true
+	}
+	return bridge, nil
+}
+
+// Close removes the forwarding rules added by SetupBridge. The bridge itself is
+// kept, along with the NAT rules, which are reused by the next run.
+func (bridge *Bridge) Close() error {
+	if !bridge.forwarding {
+		return nil
+	}
+	if err := removeForwarding(); err != nil {
+		return fmt.Errorf("unable to remove forwarding rules: %s", err)
+	}
+	bridge.forwarding = false
+	return nil
+}
+
+// CanForwardPorts returns true if ports can be forwarded to jobs.
+func (bridge *Bridge) CanForwardPorts() bool {
+	return bridge.nat
+}
+
+// Attach connects the net namespace of the process with the given PID to the
+// bridge, and forwards ports of the host to it. It returns the configuration to
+// apply inside the namespace.
+func (bridge *Bridge) Attach(jobId string, pid int, ports []PortForward) (Config, error) {
+	address, err := bridge.allocate(jobId, ports)
+	if err != nil {
+		return Config{}, err
+	}
+
+	hostName, peerName := vethNames(addressIndex(bridge.subnet, address))
+	veth := &netlink.Veth{
+		LinkAttrs: netlink.LinkAttrs{Name: hostName, MasterIndex: bridge.link.Attrs().Index},
+		PeerName:  peerName,
+	}
+	if err := netlink.LinkAdd(veth); err != nil {
+		bridge.Detach(jobId)
+		return Config{}, fmt.Errorf("unable to create veth pair: %s", err)
+	}
+	if err := bridge.attach(veth, peerName, pid); err != nil {
+		// The job's end goes away with the host's end.
+		netlink.LinkDel(veth)
+		bridge.Detach(jobId)
+		return Config{}, err
+	}
+
+	for _, port := range ports {
+		if err := forwardPort(port, address); err != nil {
+			netlink.LinkDel(veth)
+			bridge.Detach(jobId)
+			return Config{}, err
+		}
+		bridge.mu.Lock()
+		bridge.forwards[jobId] = append(bridge.forwards[jobId], port)
+		bridge.mu.Unlock()
+	}
+
+	ones, _ := bridge.subnet.Mask.Size()
+	return Config{
+		Mode:      ModeBridge,
+		Interface: peerName,
+		Address:   fmt.Sprintf("%s/%d", address, ones),
+		Gateway:   bridge.gateway.IP.String(),
+	}, nil
+}
+
+// attach brings up the host's end of the veth pair and moves the job's end
+// into the net namespace of the process with the given PID.
+func (bridge *Bridge) attach(veth *netlink.Veth, peerName string, pid int) error {
+	if err := netlink.LinkSetUp(veth); err != nil {
+		return fmt.Errorf("unable to bring up %s: %s", veth.Name, err)
+	}
+	peer, err := netlink.LinkByName(peerName)
+	if err != nil {
+		return fmt.Errorf("unable to find %s: %s", peerName, err)
+	}
+	if err := netlink.LinkSetNsPid(peer, pid); err != nil {
+		return fmt.Errorf("unable to move %s into the job's net namespace: %s", peerName, err)
+	}
+	return nil
+}
+
+// Detach removes the port forwards and the veth pair of a job, and releases its
+// address. The veth pair is removed right away, rather than along with the
+// job's net namespace, since its names are reused with the address.
+func (bridge *Bridge) Detach(jobId string) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+
+	address, ok := bridge.addresses[jobId]
+	if !ok {
+		return
+	}
+	hostName, _ := vethNames(addressIndex(bridge.subnet, address))
+	if link, err := netlink.LinkByName(hostName); err == nil {
+		if err := netlink.LinkDel(link); err != nil {
+			log.Errorf("Unable to remove veth pair of job (%s): %s", jobId, err)
+		}
+	}
+	for _, port := range bridge.forwards[jobId] {
+		if err := removePortForward(port, address); err != nil {
+			log.Errorf("Unable to remove port forward %s of job (%s): %s", port, jobId, err)
+		}
+	}
+	for key, owner := range bridge.ports {
+		if owner == jobId {
+			delete(bridge.ports, key)
+		}
+	}
+	delete(bridge.forwards, jobId)
+	delete(bridge.addresses, jobId)
+}
+
+// allocate reserves an address and the host ports for a job.
+func (bridge *Bridge) allocate(jobId string, ports []PortForward) (net.IP, error) {
+	bridge.mu.Lock()
+	defer bridge.mu.Unlock()
+
+	if len(ports) > 0 && !bridge.nat {
+		return nil, fmt.Errorf("ports cannot be forwarded without NAT")
+	}
+	keys := []string{}
+	for _, port := range ports {
+		key := fmt.Sprintf("%d/%s", port.HostPort, port.Protocol)
+		if owner, ok := bridge.ports[key]; ok {
+			return nil, fmt.Errorf("host port %s is already forwarded to job %s", key, owner)
+		}
+		if contains(keys, key) {
+			return nil, fmt.Errorf("host port %s is forwarded more than once", key)
+		}
+		keys = append(keys, key)
+	}
+
+	address := freeAddress(bridge.subnet, bridge.addresses)
+	if address == nil {
+		return nil, fmt.Errorf("no addresses left in %s", bridge.subnet)
+	}
+	bridge.addresses[jobId] = address
+	for _, key := range keys {
+		bridge.ports[key] = jobId
+	}
+	return address, nil
+}
+
+// freeAddress returns the first address of subnet that is not used. The network
+// address, the gateway, and the broadcast address are never used.
+func freeAddress(subnet *net.IPNet, used map[string]net.IP) net.IP {
+	ones, bits := subnet.Mask.Size()
+	size := 1 << uint(bits-ones)
+	for n := 2; n < size-1; n++ {
+		address := nthAddress(subnet, n)
+		free := true
+		for _, ip := range used {
+			if ip.Equal(address) {
+				free = false
+				break
+			}
+		}
+		if free {
+			return address
+		}
+	}
+	return nil
+}
+
+// nthAddress returns the address n addresses after the start of subnet.
+func nthAddress(subnet *net.IPNet, n int) net.IP {
+	address := make(net.IP, len(subnet.IP))
+	copy(address, subnet.IP)
+	for i := len(address) - 1; i >= 0 && n > 0; i-- {
+		sum := int(address[i]) + n
+		address[i] = byte(sum)
+		n = sum >> 8
+	}
+	return address
+}
+
+// addressIndex returns how many addresses address comes after the start of
+// subnet. It is the inverse of nthAddress.
+func addressIndex(subnet *net.IPNet, address net.IP) int {
+	n := 0
+	for i := range subnet.IP {
+		n = n<<8 | int(address[i]&^subnet.Mask[i])
+	}
+	return n
+}
+
+// vethNames returns the names of the host's and the job's end of the veth pair
+// of the job with the nth address of the subnet. Addresses are unique, so the
+// names never collide, and they fit the 15 characters allowed for interface
+// names.
+func vethNames(n int) (string, string) {
+	return fmt.Sprintf("rjobh%d", n), fmt.Sprintf("rjobp%d", n)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
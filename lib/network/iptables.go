@@ -0,0 +1,106 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// natChain holds the port forwards of jobs. It is flushed when the server
+// starts, which removes the forwards left behind by a previous run.
+const natChain = "RJOB"
+
+// setupNat masquerades traffic leaving subnet for other networks, and prepares
+// natChain for port forwards. Netlink cannot manage NAT rules, so iptables is
+// used.
+func setupNat(subnet string) error {
+	if _, err := exec.LookPath("iptables"); err != nil {
+		return fmt.Errorf("iptables is required for NAT: %s", err)
+	}
+	if iptables("-t", "nat", "-L", natChain, "-n") != nil {
+		if err := iptables("-t", "nat", "-N", natChain); err != nil {
+			return err
+		}
+	}
+	if err := iptables("-t", "nat", "-F", natChain); err != nil {
+		return err
+	}
+
+	rules := [][]string{
+		{"POSTROUTING", "-s", subnet, "!", "-o", BridgeName, "-j", "MASQUERADE"},
+		{"PREROUTING", "-m", "addrtype", "--dst-type", "LOCAL", "-j", natChain},
+		{"OUTPUT", "-m", "addrtype", "--dst-type", "LOCAL", "-j", natChain},
+	}
+	for _, rule := range rules {
+		if err := ensureRule("nat", rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forwardRules let traffic from jobs be forwarded, along with the replies to
+// it, when the host drops forwarded traffic by default.
+var forwardRules = [][]string{
+	{"FORWARD", "-i", BridgeName, "-j", "ACCEPT"},
+	{"FORWARD", "-o", BridgeName, "-m", "conntrack", "--ctstate", "RELATED,ESTABLISHED", "-j", "ACCEPT"},
+}
+
+// setupForwarding adds forwardRules to the filter table.
+func setupForwarding() error {
+	for _, rule := range forwardRules {
+		if err := ensureRule("filter", rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeForwarding removes the rules added by setupForwarding.
+func removeForwarding() error {
+	for _, rule := range forwardRules {
+		if err := iptables(append([]string{"-t", "filter", "-D"}, rule...)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ensureRule appends a rule to a chain of table, unless it exists.
+func ensureRule(table string, rule []string) error {
+	if iptables(append([]string{"-t", table, "-C"}, rule...)...) == nil {
+		return nil
+	}
+	return iptables(append([]string{"-t", table, "-A"}, rule...)...)
+}
+
+// forwardPort forwards a port of the host to a port of the job at address.
+func forwardPort(port PortForward, address net.IP) error {
+	return iptables(append([]string{"-t", "nat", "-A", natChain}, portForwardRule(port, address)...)...)
+}
+
+// removePortForward removes a port forward added by forwardPort.
+func removePortForward(port PortForward, address net.IP) error {
+	return iptables(append([]string{"-t", "nat", "-D", natChain}, portForwardRule(port, address)...)...)
+}
+
+// portForwardRule returns the rule of natChain forwarding a port.
+func portForwardRule(port PortForward, address net.IP) []string {
+	return []string{
+		"-p", port.Protocol,
+		"--dport", strconv.Itoa(int(port.HostPort)),
+		"-j", "DNAT",
+		"--to-destination", net.JoinHostPort(address.String(), strconv.Itoa(int(port.JobPort))),
+	}
+}
+
+// iptables runs iptables with args.
+func iptables(args ...string) error {
+	out, err := exec.Command("iptables", append([]string{"-w"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("iptables %s: %s: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
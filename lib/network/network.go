@@ -0,0 +1,140 @@
+package network
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/vishvananda/netlink"
+)
+
+// Network modes of jobs.
+const (
+	// ModeNone gives the job a net namespace with only loopback.
+	ModeNone = "none"
+	// ModeHost shares the network of the host with the job.
+	ModeHost = "host"
+	// ModeBridge connects the job's net namespace to a bridge on the host,
+	// through which it reaches other networks using NAT.
+	ModeBridge = "bridge"
+)
+
+// Protocols that ports can be forwarded for.
+const (
+	ProtocolTcp = "tcp"
+	ProtocolUdp = "udp"
+)
+
+// jobInterface is the name of the job's end of the veth pair once it has been
+// moved into the job's net namespace.
+const jobInterface = "eth0"
+
+// Config is the network configuration applied by rjob's reexec operation in the
+// job's net namespace.
+type Config struct {
+	Mode string `json:"mode"`
+	// Interface is the job's end of the veth pair, as named on the host.
+	// Address is in CIDR notation. They are only set in ModeBridge.
+	Interface string `json:"interface,omitempty"`
+	Address   string `json:"address,omitempty"`
+	Gateway   string `json:"gateway,omitempty"`
+}
+
+// PortForward forwards a port of the host to a port of a job.
+type PortForward struct {
+	HostPort uint16
+	JobPort  uint16
+	Protocol string
+}
+
+// ValidateMode returns an error if mode is not a known network mode.
+func ValidateMode(mode string) error {
+	switch mode {
+	case ModeNone, ModeHost, ModeBridge:
+		return nil
+	}
+	return fmt.Errorf("unknown network mode: %s", mode)
+}
+
+// Validate returns an error if the ports or the protocol are invalid.
+func (port PortForward) Validate() error {
+	if port.HostPort == 0 || port.JobPort == 0 {
+		return fmt.Errorf("ports must be between 1 and 65535")
+	}
+	if port.Protocol != ProtocolTcp && port.Protocol != ProtocolUdp {
+		return fmt.Errorf("unknown protocol: %s", port.Protocol)
+	}
+	return nil
+}
+
+func (port PortForward) String() string {
+	return fmt.Sprintf("%d:%d/%s", port.HostPort, port.JobPort, port.Protocol)
+}
+
+// ParsePortForward parses a port forward in the format HOST:JOB[/PROTOCOL],
+// e.g. 8080:80/tcp. The protocol defaults to tcp.
+func ParsePortForward(value string) (PortForward, error) {
+	port := PortForward{Protocol: ProtocolTcp}
+	if i := strings.Index(value, "/"); i != -1 {
+		port.Protocol = value[i+1:]
+		value = value[:i]
+	}
+	ports := strings.SplitN(value, ":", 2)
+	if len(ports) != 2 {
+		return port, fmt.Errorf("invalid port forward %q, expected HOST:JOB[/PROTOCOL]", value)
+	}
+	hostPort, err := strconv.ParseUint(ports[0], 10, 16)
+	if err != nil {
+		return port, fmt.Errorf("invalid host port: %s", ports[0])
+	}
+	jobPort, err := strconv.ParseUint(ports[1], 10, 16)
+	if err != nil {
+		return port, fmt.Errorf("invalid job port: %s", ports[1])
+	}
+	port.HostPort, port.JobPort = uint16(hostPort), uint16(jobPort)
+	return port, port.Validate()
+}
+
+// Configure applies cfg in the current net namespace. Loopback is brought up in
+// every mode. In ModeBridge, the job's end of the veth pair is renamed, given
+// its address, and used for the default route.
+func Configure(cfg Config) error {
+	lo, err := netlink.LinkByName("lo")
+	if err != nil {
+		return fmt.Errorf("unable to find loopback: %s", err)
+	}
+	if err := netlink.LinkSetUp(lo); err != nil {
+		return fmt.Errorf("unable to bring up loopback: %s", err)
+	}
+	if cfg.Mode != ModeBridge {
+		return nil
+	}
+
+	link, err := netlink.LinkByName(cfg.Interface)
+	if err != nil {
+		return fmt.Errorf("unable to find interface %s: %s", cfg.Interface, err)
+	}
+	if err := netlink.LinkSetName(link, jobInterface); err != nil {
+		return fmt.Errorf("unable to rename interface %s: %s", cfg.Interface, err)
+	}
+	addr, err := netlink.ParseAddr(cfg.Address)
+	if err != nil {
+		return fmt.Errorf("invalid address %s: %s", cfg.Address, err)
+	}
+	if err := netlink.AddrAdd(link, addr); err != nil {
+		return fmt.Errorf("unable to add address %s: %s", cfg.Address, err)
+	}
+	if err := netlink.LinkSetUp(link); err != nil {
+		return fmt.Errorf("unable to bring up %s: %s", jobInterface, err)
+	}
+	gateway := net.ParseIP(cfg.Gateway)
+	if gateway == nil {
+		return fmt.Errorf("invalid gateway: %s", cfg.Gateway)
+	}
+	route := &netlink.Route{LinkIndex: link.Attrs().Index, Gw: gateway}
+	if err := netlink.RouteAdd(route); err != nil {
+		return fmt.Errorf("unable to add default route: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// +build integration
+
+package network
+
+import (
+	"net"
+	"runtime"
+	"testing"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+func TestConfigureLoopback(t *testing.T) {
+	result := make(chan error)
+	go func() {
+		// The thread is left in the new net namespace, so it is never
+		// unlocked and exits along with the goroutine.
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNET); err != nil {
+			result <- err
+			return
+		}
+		if err := Configure(Config{Mode: ModeNone}); err != nil {
+			result <- err
+			return
+		}
+		lo, err := netlink.LinkByName("lo")
+		if err == nil && lo.Attrs().Flags&net.FlagUp == 0 {
+			t.Errorf("expected loopback to be up")
+		}
+		result <- err
+	}()
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+}
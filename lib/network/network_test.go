@@ -0,0 +1,107 @@
+// +build unit
+
+package network
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestParsePortForward(t *testing.T) {
+	testCases := []struct {
+		name           string
+		value          string
+		expectedResult PortForward
+		expectedError  bool
+	}{
+		{name: "DefaultProtocol", value: "8080:80", expectedResult: PortForward{HostPort: 8080, JobPort: 80, Protocol: "tcp"}},
+		{name: "Udp", value: "53:5353/udp", expectedResult: PortForward{HostPort: 53, JobPort: 5353, Protocol: "udp"}},
+		{name: "MissingJobPort", value: "8080", expectedError: true},
+		{name: "ZeroPort", value: "0:80", expectedError: true},
+		{name: "PortOverMax", value: "8080:65536", expectedError: true},
+		{name: "UnknownProtocol", value: "8080:80/sctp", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := ParsePortForward(tcase.value)
+		switch {
+		case tcase.expectedError && err == nil:
+			t.Errorf("test %s: error expected, but was not received", tcase.name)
+		case !tcase.expectedError && err != nil:
+			t.Errorf("test %s: received error: %s", tcase.name, err)
+		case !tcase.expectedError && result != tcase.expectedResult:
+			t.Errorf("test %s: expected: %+v, got: %+v", tcase.name, tcase.expectedResult, result)
+		}
+	}
+}
+
+func TestFreeAddress(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("10.88.0.0/30")
+	used := map[string]net.IP{}
+	address := freeAddress(subnet, used)
+	if !address.Equal(net.ParseIP("10.88.0.2")) {
+		t.Errorf("expected: 10.88.0.2, got: %s", address)
+	}
+	used["job"] = address
+	if address := freeAddress(subnet, used); address != nil {
+		t.Errorf("expected no address left, got: %s", address)
+	}
+
+	_, subnet, _ = net.ParseCIDR("10.88.0.0/16")
+	if address := nthAddress(subnet, 258); !address.Equal(net.ParseIP("10.88.1.2")) {
+		t.Errorf("expected: 10.88.1.2, got: %s", address)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR(Subnet)
+	bridge := &Bridge{
+		subnet:    subnet,
+		nat:       true,
+		addresses: map[string]net.IP{},
+		ports:     map[string]string{},
+		forwards:  map[string][]PortForward{},
+	}
+	ports := []PortForward{{HostPort: 8080, JobPort: 80, Protocol: "tcp"}}
+	first, err := bridge.allocate("job1", ports)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bridge.allocate("job2", ports); err == nil {
+		t.Errorf("error expected for a host port forwarded twice, but was not received")
+	}
+	second, err := bridge.allocate("job2", []PortForward{{HostPort: 8080, JobPort: 80, Protocol: "udp"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Equal(second) {
+		t.Errorf("expected different addresses, got: %s", first)
+	}
+
+	bridge.nat = false
+	if _, err := bridge.allocate("job3", []PortForward{{HostPort: 9090, JobPort: 90, Protocol: "tcp"}}); err == nil {
+		t.Errorf("error expected for ports forwarded without NAT, but was not received")
+	}
+}
+
+func TestPortForwardRule(t *testing.T) {
+	rule := portForwardRule(PortForward{HostPort: 8080, JobPort: 80, Protocol: "tcp"}, net.ParseIP("10.88.0.2"))
+	expected := "-p tcp --dport 8080 -j DNAT --to-destination 10.88.0.2:80"
+	if strings.Join(rule, " ") != expected {
+		t.Errorf("expected: %s, got: %s", expected, strings.Join(rule, " "))
+	}
+}
+
+func TestVethNames(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR(Subnet)
+	host, peer := vethNames(addressIndex(subnet, nthAddress(subnet, 258)))
+	if host != "rjobh258" || peer != "rjobp258" {
+		t.Errorf("unexpected names: %s, %s", host, peer)
+	}
+
+	// The last address of the subnet has the longest names.
+	ones, bits := subnet.Mask.Size()
+	if _, peer := vethNames(1<<uint(bits-ones) - 2); len(peer) > 15 {
+		t.Errorf("interface name too long: %s", peer)
+	}
+}
@@ -0,0 +1,212 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/bill-rich/rjob/lib/command"
+)
+
+// Policy restricts what clients may ask for when they start jobs. Clients are
+// identified by the common name of their certificate.
+type Policy struct {
+	// Default applies to clients without rules of their own.
+	Default Rules `json:"default"`
+	// Clients holds the rules of individual clients.
+	Clients map[string]Rules `json:"clients"`
+}
+
+// Rules are what a client may ask for.
+type Rules struct {
+	// RequiredNamespaces must be used by every job. Jobs may use other
+	// namespaces as well.
+	RequiredNamespaces []string `json:"required_namespaces"`
+	// AllowedPaths are the host directories, along with everything below
+	// them, that may be used as root filesystems or mounted into jobs.
+	AllowedPaths []string `json:"allowed_paths"`
+	// Users are the accounts jobs may run as. Jobs that do not select a
+	// user run as the first one.
+	Users []string `json:"users"`
+	// Groups are the groups jobs may run with, besides the primary group
+	// of their user.
+	Groups []string `json:"groups"`
+	// Profiles are the security profiles jobs may use. Jobs that do not
+	// select a profile use the first one, or none if none are listed.
+	Profiles []string `json:"profiles"`
+}
+
+// DefaultUser is the only account jobs may run as when no policy file is given.
+const DefaultUser = "nobody"
+
+// Default returns the policy used when no policy file is given. Every job must
+// use the default namespaces and run as DefaultUser, and no host paths are
+// allowed.
+func Default() *Policy {
+	return &Policy{
+		Default: Rules{
+			RequiredNamespaces: command.DefaultNamespaces,
+			Users:              []string{DefaultUser},
+		},
+	}
+}
+
+// Load reads a policy from a JSON file.
+func Load(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading policy %s: %s", path, err)
+	}
+	p := &Policy{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, fmt.Errorf("error parsing policy %s: %s", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %s", path, err)
+	}
+	return p, nil
+}
+
+// For returns the rules of a client.
+func (p *Policy) For(client string) Rules {
+	if rules, ok := p.Clients[client]; ok {
+		return rules
+	}
+	return p.Default
+}
+
+// CheckNamespaces returns an error unless the namespaces include every
+// required namespace.
+func (r Rules) CheckNamespaces(namespaces []string) error {
+	for _, ns := range r.RequiredNamespaces {
+		if !command.HasNamespace(namespaces, ns) {
+			return fmt.Errorf("policy requires the %s namespace", ns)
+		}
+	}
+	return nil
+}
+
+// CheckPath returns an error unless path is one of the allowed paths, or below
+// one of them. The path must not contain symlinks.
+func (r Rules) CheckPath(path string) error {
+	for _, allowed := range r.AllowedPaths {
+		rel, err := filepath.Rel(allowed, path)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return nil
+		}
+	}
+	return fmt.Errorf("policy does not allow the path %s", path)
+}
+
+// Credential returns the identity a job runs with, if the client may use it.
+// The user defaults to the first allowed user, and the group to the primary
+// group of the user. Users and groups are looked up by name on the host.
+func (r Rules) Credential(userName, groupName string, groupNames []string) (*syscall.Credential, error) {
+	if userName == "" {
+		if len(r.Users) == 0 {
+			return nil, fmt.Errorf("policy does not allow any user")
+		}
+		userName = r.Users[0]
+	}
+	if !contains(r.Users, userName) {
+		return nil, fmt.Errorf("policy does not allow the user %s", userName)
+	}
+	account, err := user.Lookup(userName)
+	if err != nil {
+		return nil, err
+	}
+	uid, err := strconv.ParseUint(account.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid uid of user %s: %s", userName, account.Uid)
+	}
+
+	// The primary group of the user is always allowed.
+	allowed := r.Groups
+	if primary, err := user.LookupGroupId(account.Gid); err == nil {
+		allowed = append([]string{primary.Name}, allowed...)
+	}
+	lookupGroup := func(name string) (uint32, error) {
+		if !contains(allowed, name) {
+			return 0, fmt.Errorf("policy does not allow the group %s", name)
+		}
+		group, err := user.LookupGroup(name)
+		if err != nil {
+			return 0, err
+		}
+		gid, err := strconv.ParseUint(group.Gid, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid gid of group %s: %s", name, group.Gid)
+		}
+		return uint32(gid), nil
+	}
+
+	credential := &syscall.Credential{Uid: uint32(uid), Groups: []uint32{}}
+	if groupName == "" {
+		gid, err := strconv.ParseUint(account.Gid, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gid of user %s: %s", userName, account.Gid)
+		}
+		credential.Gid = uint32(gid)
+	} else if credential.Gid, err = lookupGroup(groupName); err != nil {
+		return nil, err
+	}
+	for _, name := range groupNames {
+		gid, err := lookupGroup(name)
+		if err != nil {
+			return nil, err
+		}
+		credential.Groups = append(credential.Groups, gid)
+	}
+	return credential, nil
+}
+
+// Profile returns the security profile a job uses, if the client may use it.
+// An empty name selects the default profile, which is no profile if none are
+// allowed.
+func (r Rules) Profile(name string) (string, error) {
+	if name == "" {
+		if len(r.Profiles) == 0 {
+			return "", nil
+		}
+		return r.Profiles[0], nil
+	}
+	if !contains(r.Profiles, name) {
+		return "", fmt.Errorf("policy does not allow the profile %s", name)
+	}
+	return name, nil
+}
+
+func (p *Policy) validate() error {
+	if err := p.Default.validate(); err != nil {
+		return fmt.Errorf("default rules: %s", err)
+	}
+	for client, rules := range p.Clients {
+		if err := rules.validate(); err != nil {
+			return fmt.Errorf("rules of %s: %s", client, err)
+		}
+	}
+	return nil
+}
+
+func (r Rules) validate() error {
+	for _, path := range r.AllowedPaths {
+		if !filepath.IsAbs(path) {
+			return fmt.Errorf("allowed path is not absolute: %s", path)
+		}
+	}
+	return command.ValidateNamespaces(r.RequiredNamespaces)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
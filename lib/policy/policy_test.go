@@ -0,0 +1,111 @@
+// +build unit
+
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policy.json")
+	data := `{
+		"default": {"required_namespaces": ["pid", "mount", "net"]},
+		"clients": {"builder": {"required_namespaces": ["pid", "mount"]}}
+	}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.For("builder").RequiredNamespaces) != 2 {
+		t.Errorf("expected 2 required namespaces for builder, got: %v", p.For("builder").RequiredNamespaces)
+	}
+	if len(p.For("other").RequiredNamespaces) != 3 {
+		t.Errorf("expected default rules for other, got: %v", p.For("other").RequiredNamespaces)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"default": {"required_namespaces": ["time"]}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Errorf("error expected for unknown namespace, but was not received")
+	}
+}
+
+func TestCheckNamespaces(t *testing.T) {
+	rules := Default().For("client")
+	if err := rules.CheckNamespaces([]string{"pid", "mount", "net", "uts"}); err != nil {
+		t.Errorf("received error for namespaces including all required ones: %s", err)
+	}
+	if err := rules.CheckNamespaces([]string{"pid", "mount"}); err == nil {
+		t.Errorf("error expected without the net namespace, but was not received")
+	}
+	if err := (Rules{}).CheckNamespaces([]string{}); err != nil {
+		t.Errorf("received error without required namespaces: %s", err)
+	}
+}
+
+func TestCheckPath(t *testing.T) {
+	rules := Rules{AllowedPaths: []string{"/srv/images", "/data"}}
+	for _, path := range []string{"/srv/images", "/srv/images/base", "/data/a/b"} {
+		if err := rules.CheckPath(path); err != nil {
+			t.Errorf("received error for allowed path %s: %s", path, err)
+		}
+	}
+	for _, path := range []string{"/srv", "/srv/images2", "/etc", "/"} {
+		if err := rules.CheckPath(path); err == nil {
+			t.Errorf("error expected for path %s, but was not received", path)
+		}
+	}
+	if err := Default().For("client").CheckPath("/data"); err == nil {
+		t.Errorf("error expected for the default policy, but was not received")
+	}
+}
+
+func TestCredential(t *testing.T) {
+	rules := Rules{Users: []string{"root"}, Groups: []string{"root"}}
+	credential, err := rules.Credential("", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if credential.Uid != 0 || credential.Gid != 0 || len(credential.Groups) != 0 {
+		t.Errorf("expected root without supplementary groups, got: %+v", credential)
+	}
+	if credential, err = rules.Credential("root", "root", []string{"root"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(credential.Groups) != 1 || credential.Groups[0] != 0 {
+		t.Errorf("expected the root group as supplementary group, got: %v", credential.Groups)
+	}
+
+	if _, err := rules.Credential("nobody", "", nil); err == nil {
+		t.Errorf("error expected for a user that is not allowed, but was not received")
+	}
+	if _, err := (Rules{Users: []string{"root"}}).Credential("", "", []string{"nogroup"}); err == nil {
+		t.Errorf("error expected for a group that is not allowed, but was not received")
+	}
+	if _, err := (Rules{}).Credential("", "", nil); err == nil {
+		t.Errorf("error expected without allowed users, but was not received")
+	}
+}
+
+func TestProfile(t *testing.T) {
+	rules := Rules{Profiles: []string{"restricted", "locked"}}
+	if name, err := rules.Profile(""); err != nil || name != "restricted" {
+		t.Errorf("expected the first profile, got: %s (%v)", name, err)
+	}
+	if name, err := rules.Profile("locked"); err != nil || name != "locked" {
+		t.Errorf("expected: locked, got: %s (%v)", name, err)
+	}
+	if _, err := rules.Profile("unconfined"); err == nil {
+		t.Errorf("error expected for a profile that is not allowed, but was not received")
+	}
+	if name, err := (Rules{}).Profile(""); err != nil || name != "" {
+		t.Errorf("expected no profile, got: %s (%v)", name, err)
+	}
+}
@@ -0,0 +1,346 @@
+package rootfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// MountDir is where the root filesystem of a job is assembled before the job
+// pivots into it. Each job assembles its root in its own mount namespace, so
+// jobs can share the directory. This could be read from a configuration.
+const MountDir = "/tmp/rjob/rootfs"
+
+// mountinfoPath lists the mounts of the mount namespace of the current thread,
+// which may differ from that of the process.
+const mountinfoPath = "/proc/thread-self/mountinfo"
+
+// devices are bind mounted from the host into the /dev of jobs.
+var devices = []string{"null", "zero", "full", "random", "urandom", "tty"}
+
+// Config is the root filesystem of a job.
+type Config struct {
+	// Layers are directories making up the root filesystem, lowest first,
+	// such as the unpacked layers of an OCI image. They are stacked using
+	// overlayfs, with a writable layer on top that is discarded along with
+	// the job, so the layers themselves are never changed by jobs.
+	Layers []string
+	// Mounts are host paths bind mounted into the root filesystem.
+	Mounts []Mount
+}
+
+// Mount bind mounts Source from the host at Target in the job.
+type Mount struct {
+	Source   string
+	Target   string
+	ReadOnly bool
+}
+
+// ParseMount parses a mount in the format SOURCE:TARGET[:ro|rw]. Mounts are
+// writable unless ro is given.
+func ParseMount(value string) (Mount, error) {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Mount{}, fmt.Errorf("invalid mount %q, expected SOURCE:TARGET[:ro|rw]", value)
+	}
+	mount := Mount{Source: parts[0], Target: parts[1]}
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "ro":
+			mount.ReadOnly = true
+		case "rw":
+		default:
+			return Mount{}, fmt.Errorf("invalid mount option %q, expected ro or rw", parts[2])
+		}
+	}
+	return mount, mount.Validate()
+}
+
+func (mount Mount) String() string {
+	if mount.ReadOnly {
+		return mount.Source + ":" + mount.Target + ":ro"
+	}
+	return mount.Source + ":" + mount.Target + ":rw"
+}
+
+// Validate returns an error unless both paths of the mount are absolute.
+func (mount Mount) Validate() error {
+	if !filepath.IsAbs(mount.Source) || !filepath.IsAbs(mount.Target) {
+		return fmt.Errorf("mount paths must be absolute: %s", mount)
+	}
+	if filepath.Clean(mount.Target) == "/" {
+		return fmt.Errorf("cannot mount over the root of the job: %s", mount)
+	}
+	return nil
+}
+
+// Setup assembles the root filesystem in MountDir, and makes it the root of the
+// current mount namespace. A new /proc is mounted for the current PID
+// namespace, along with a minimal /dev and an empty /tmp.
+func Setup(cfg Config) error {
+	// Mounts must not propagate back to the host.
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("unable to make mounts private: %s", err)
+	}
+	if err := os.MkdirAll(MountDir, 0755); err != nil {
+		return err
+	}
+
+	root, err := mountLayers(cfg.Layers)
+	if err != nil {
+		return err
+	}
+	if err := mountSpecial(root); err != nil {
+		return err
+	}
+	for _, mount := range cfg.Mounts {
+		if err := bindMount(root, mount); err != nil {
+			return err
+		}
+	}
+	return pivotRoot(root)
+}
+
+// mountLayers mounts the layers of the root filesystem, and returns where the
+// root was mounted.
+func mountLayers(layers []string) (string, error) {
+	if len(layers) == 0 {
+		return "", fmt.Errorf("root filesystem has no layers")
+	}
+
+	// The writable layer and the work directory of overlayfs are kept in
+	// memory.
+	if err := unix.Mount("tmpfs", MountDir, "tmpfs", 0, "mode=755"); err != nil {
+		return "", fmt.Errorf("unable to mount tmpfs at %s: %s", MountDir, err)
+	}
+	upper, work, root := filepath.Join(MountDir, "upper"), filepath.Join(MountDir, "work"), filepath.Join(MountDir, "root")
+	for _, dir := range []string{upper, work, root} {
+		if err := os.Mkdir(dir, 0755); err != nil {
+			return "", err
+		}
+	}
+	// overlayfs lists the lower layers from the top down.
+	lower := make([]string, len(layers))
+	for i, layer := range layers {
+		lower[len(layers)-1-i] = layer
+	}
+	options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", strings.Join(lower, ":"), upper, work)
+	if err := unix.Mount("overlay", root, "overlay", 0, options); err != nil {
+		return "", fmt.Errorf("unable to mount layers: %s", err)
+	}
+	return root, nil
+}
+
+// mountSpecial mounts /proc, /dev, and /tmp in the root filesystem.
+func mountSpecial(root string) error {
+	mounts := []struct {
+		source, target, fstype string
+		flags                  uintptr
+		options                string
+	}{
+		{"proc", "/proc", "proc", unix.MS_NOSUID | unix.MS_NODEV | unix.MS_NOEXEC, ""},
+		{"tmpfs", "/dev", "tmpfs", unix.MS_NOSUID, "mode=755"},
+		{"devpts", "/dev/pts", "devpts", unix.MS_NOSUID | unix.MS_NOEXEC, "newinstance,ptmxmode=0666,mode=0620"},
+		{"tmpfs", "/dev/shm", "tmpfs", unix.MS_NOSUID | unix.MS_NODEV, "mode=1777"},
+		{"tmpfs", "/tmp", "tmpfs", unix.MS_NOSUID | unix.MS_NODEV, "mode=1777"},
+	}
+	for _, m := range mounts {
+		target, err := resolve(root, m.target)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return err
+		}
+		if err := unix.Mount(m.source, target, m.fstype, m.flags, m.options); err != nil {
+			return fmt.Errorf("unable to mount %s: %s", m.target, err)
+		}
+	}
+
+	dev := filepath.Join(root, "dev")
+	for _, device := range devices {
+		if err := bindFile(filepath.Join("/dev", device), filepath.Join(dev, device)); err != nil {
+			return fmt.Errorf("unable to mount /dev/%s: %s", device, err)
+		}
+	}
+	links := map[string]string{
+		"ptmx":   "pts/ptmx",
+		"fd":     "/proc/self/fd",
+		"stdin":  "/proc/self/fd/0",
+		"stdout": "/proc/self/fd/1",
+		"stderr": "/proc/self/fd/2",
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dev, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindMount bind mounts a host path into the root filesystem.
+func bindMount(root string, mount Mount) error {
+	target, err := resolve(root, mount.Target)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(mount.Source)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return err
+		}
+		err = unix.Mount(mount.Source, target, "", unix.MS_BIND|unix.MS_REC, "")
+	} else {
+		err = bindFile(mount.Source, target)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to mount %s: %s", mount, err)
+	}
+	if mount.ReadOnly {
+		if err := remountReadOnly(target); err != nil {
+			return fmt.Errorf("unable to make %s read-only: %s", mount.Target, err)
+		}
+	}
+	return nil
+}
+
+// remountReadOnly makes the mount at target read-only, along with every mount
+// below it. Bind mounts can only be made read-only by remounting them, and a
+// remount only applies to a single mount, not to the mounts below it.
+func remountReadOnly(target string) error {
+	mountinfo, err := os.ReadFile(mountinfoPath)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %s", mountinfoPath, err)
+	}
+	for _, point := range submounts(mountinfo, target) {
+		stat := unix.Statfs_t{}
+		if err := unix.Statfs(point, &stat); err != nil {
+			return err
+		}
+		// Flags such as nosuid may be locked on mounts from another user
+		// namespace, and the remount fails unless they are kept.
+		flags := uintptr(unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY) | lockedFlags(stat.Flags)
+		if err := unix.Mount("", point, "", flags, ""); err != nil {
+			return fmt.Errorf("unable to remount %s: %s", point, err)
+		}
+	}
+	return nil
+}
+
+// lockedFlags returns the mount flags matching the flags reported by statfs
+// that cannot be cleared when remounting.
+func lockedFlags(statFlags int64) uintptr {
+	flags := uintptr(0)
+	for st, ms := range map[int64]uintptr{
+		unix.ST_NOSUID:     unix.MS_NOSUID,
+		unix.ST_NODEV:      unix.MS_NODEV,
+		unix.ST_NOEXEC:     unix.MS_NOEXEC,
+		unix.ST_NOATIME:    unix.MS_NOATIME,
+		unix.ST_NODIRATIME: unix.MS_NODIRATIME,
+		unix.ST_RELATIME:   unix.MS_RELATIME,
+	} {
+		if statFlags&st != 0 {
+			flags |= ms
+		}
+	}
+	return flags
+}
+
+// submounts returns the mount points at or below target in the contents of
+// /proc/self/mountinfo, each listed once.
+func submounts(mountinfo []byte, target string) []string {
+	target = filepath.Clean(target)
+	points := []string{}
+	seen := map[string]bool{}
+	for _, line := range strings.Split(string(mountinfo), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		point := unescapeMountinfo(fields[4])
+		if point != target && !strings.HasPrefix(point, target+"/") || seen[point] {
+			continue
+		}
+		seen[point] = true
+		points = append(points, point)
+	}
+	return points
+}
+
+// unescapeMountinfo decodes the octal escapes, such as \040 for a space, used
+// in the paths of /proc/self/mountinfo.
+func unescapeMountinfo(path string) string {
+	decoded := strings.Builder{}
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				decoded.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		decoded.WriteByte(path[i])
+	}
+	return decoded.String()
+}
+
+// bindFile bind mounts the file source at target, creating target first.
+func bindFile(source, target string) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(target, os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return unix.Mount(source, target, "", unix.MS_BIND, "")
+}
+
+// resolve returns the path of target within root. The layers of the root
+// filesystem may come from an untrusted image, so symlinks in the path are
+// refused, as they could point mounts at the host's filesystem.
+func resolve(root, target string) (string, error) {
+	path := root
+	for _, name := range strings.Split(filepath.Clean("/"+target), "/") {
+		if name == "" {
+			continue
+		}
+		path = filepath.Join(path, name)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("mount target %s contains a symlink", target)
+		}
+	}
+	return filepath.Join(root, filepath.Clean("/"+target)), nil
+}
+
+// pivotRoot makes root the root of the current mount namespace, and detaches
+// the previous root so that the host's filesystem is no longer reachable.
+func pivotRoot(root string) error {
+	if err := unix.Chdir(root); err != nil {
+		return err
+	}
+	// Pivoting onto the new root itself stacks the old root on top of it,
+	// without needing a directory to put the old root in.
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("unable to pivot into %s: %s", root, err)
+	}
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("unable to detach the host's filesystem: %s", err)
+	}
+	return unix.Chdir("/")
+}
@@ -0,0 +1,92 @@
+// +build integration
+
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"golang.org/x/sys/unix"
+)
+
+// inMountNamespace runs f on a thread in a new mount namespace, so that its
+// mounts do not reach the host. The thread is never unlocked, and exits along
+// with its goroutine.
+func inMountNamespace(t *testing.T, f func() error) {
+	t.Helper()
+	done := make(chan error)
+	go func() {
+		runtime.LockOSThread()
+		if err := unix.Unshare(unix.CLONE_NEWNS); err != nil {
+			done <- err
+			return
+		}
+		if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+			done <- err
+			return
+		}
+		done <- f()
+	}()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMountLayersSingle(t *testing.T) {
+	layer := t.TempDir()
+	if err := os.WriteFile(filepath.Join(layer, "file"), []byte("image"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	inMountNamespace(t, func() error {
+		if err := os.MkdirAll(MountDir, 0755); err != nil {
+			return err
+		}
+		root, err := mountLayers([]string{layer})
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(root, "file"), []byte("changed"), 0644); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(root, "new"), []byte("new"), 0644)
+	})
+
+	// Changes made by the job must not reach the layer.
+	if data, err := os.ReadFile(filepath.Join(layer, "file")); err != nil || string(data) != "image" {
+		t.Errorf("expected the layer to be unchanged, got: %q (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(layer, "new")); !os.IsNotExist(err) {
+		t.Errorf("expected no new file in the layer, got: %v", err)
+	}
+}
+
+func TestBindMountReadOnly(t *testing.T) {
+	source, root := t.TempDir(), t.TempDir()
+	if err := os.Mkdir(filepath.Join(source, "cache"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var written []string
+	inMountNamespace(t, func() error {
+		// The source has a mount of its own below it.
+		if err := unix.Mount("tmpfs", filepath.Join(source, "cache"), "tmpfs", 0, ""); err != nil {
+			return err
+		}
+		if err := bindMount(root, Mount{Source: source, Target: "/data", ReadOnly: true}); err != nil {
+			return err
+		}
+		for _, path := range []string{"/data/file", "/data/cache/file"} {
+			if os.WriteFile(filepath.Join(root, path), []byte("test"), 0644) == nil {
+				written = append(written, path)
+			}
+		}
+		return nil
+	})
+
+	if len(written) != 0 {
+		t.Errorf("expected read-only mounts, but wrote: %v", written)
+	}
+}
@@ -0,0 +1,91 @@
+// +build unit
+
+package rootfs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseMount(t *testing.T) {
+	testCases := []struct {
+		name           string
+		value          string
+		expectedResult Mount
+		expectedError  bool
+	}{
+		{name: "Writable", value: "/data:/mnt", expectedResult: Mount{Source: "/data", Target: "/mnt"}},
+		{name: "ReadOnly", value: "/data:/mnt:ro", expectedResult: Mount{Source: "/data", Target: "/mnt", ReadOnly: true}},
+		{name: "ExplicitWritable", value: "/data:/mnt:rw", expectedResult: Mount{Source: "/data", Target: "/mnt"}},
+		{name: "MissingTarget", value: "/data", expectedError: true},
+		{name: "UnknownOption", value: "/data:/mnt:rx", expectedError: true},
+		{name: "RelativeSource", value: "data:/mnt", expectedError: true},
+		{name: "Root", value: "/data:/", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := ParseMount(tcase.value)
+		switch {
+		case tcase.expectedError && err == nil:
+			t.Errorf("test %s: error expected, but was not received", tcase.name)
+		case !tcase.expectedError && err != nil:
+			t.Errorf("test %s: received error: %s", tcase.name, err)
+		case !tcase.expectedError && result != tcase.expectedResult:
+			t.Errorf("test %s: expected: %+v, got: %+v", tcase.name, tcase.expectedResult, result)
+		}
+	}
+
+	mount := Mount{Source: "/data", Target: "/mnt", ReadOnly: true}
+	if parsed, err := ParseMount(mount.String()); err != nil || parsed != mount {
+		t.Errorf("expected: %+v, got: %+v (%v)", mount, parsed, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "usr", "lib"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("/etc", filepath.Join(root, "host")); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name           string
+		target         string
+		expectedResult string
+		expectedError  bool
+	}{
+		{name: "Existing", target: "/usr/lib", expectedResult: filepath.Join(root, "usr", "lib")},
+		{name: "Missing", target: "/data/in", expectedResult: filepath.Join(root, "data", "in")},
+		{name: "Parent", target: "/../../etc", expectedResult: filepath.Join(root, "etc")},
+		{name: "Symlink", target: "/host/passwd", expectedError: true},
+	}
+	for _, tcase := range testCases {
+		result, err := resolve(root, tcase.target)
+		switch {
+		case tcase.expectedError && err == nil:
+			t.Errorf("test %s: error expected, but was not received", tcase.name)
+		case !tcase.expectedError && err != nil:
+			t.Errorf("test %s: received error: %s", tcase.name, err)
+		case result != tcase.expectedResult:
+			t.Errorf("test %s: expected: %s, got: %s", tcase.name, tcase.expectedResult, result)
+		}
+	}
+}
+
+func TestSubmounts(t *testing.T) {
+	mountinfo := []byte("22 1 8:1 / / rw - ext4 /dev/sda1 rw\n" +
+		"30 22 0:40 / /data rw - tmpfs tmpfs rw\n" +
+		"31 30 0:41 / /data/cache rw - tmpfs tmpfs rw\n" +
+		"32 22 0:42 / /data2 rw - tmpfs tmpfs rw\n" +
+		"33 30 0:43 / /data/my\\040files rw - tmpfs tmpfs rw\n" +
+		"34 30 0:44 / /data rw - tmpfs tmpfs rw\n")
+
+	points := submounts(mountinfo, "/data/")
+	expected := []string{"/data", "/data/cache", "/data/my files"}
+	if !reflect.DeepEqual(points, expected) {
+		t.Errorf("expected: %v, got: %v", expected, points)
+	}
+}
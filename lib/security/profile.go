@@ -0,0 +1,107 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// profileExt is the extension of profile files.
+const profileExt = ".json"
+
+// profileName restricts profile names, so that they cannot leave the profile
+// directory.
+var profileName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Profile restricts what the command of a job may do. It is applied to the
+// thread starting the command, and inherited by the command.
+type Profile struct {
+	// Capabilities are kept in the capability bounding set, and all others
+	// are dropped. The bounding set is left unchanged when it is nil.
+	Capabilities []string `json:"capabilities"`
+	// NoNewPrivs keeps the command and its children from gaining
+	// privileges, such as through setuid executables or file capabilities.
+	NoNewPrivs bool `json:"no_new_privs"`
+	// Seccomp is the file holding a seccomp BPF filter, as exported by
+	// seccomp_export_bpf. Relative paths are relative to the profile.
+	Seccomp string `json:"seccomp"`
+
+	// Path is the file the profile was loaded from.
+	Path   string `json:"-"`
+	filter []unix.SockFilter
+}
+
+// ProfilePath returns the file of the named profile in dir.
+func ProfilePath(dir, name string) (string, error) {
+	if !profileName.MatchString(name) {
+		return "", fmt.Errorf("invalid profile name: %s", name)
+	}
+	if dir == "" {
+		return "", fmt.Errorf("no profile directory is configured")
+	}
+	return filepath.Join(dir, name+profileExt), nil
+}
+
+// Load reads a profile, along with its seccomp filter.
+func Load(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading profile %s: %s", path, err)
+	}
+	profile := &Profile{Path: path}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return nil, fmt.Errorf("error parsing profile %s: %s", path, err)
+	}
+	if _, err := capabilities(profile.Capabilities); err != nil {
+		return nil, fmt.Errorf("invalid profile %s: %s", path, err)
+	}
+	if profile.Seccomp != "" {
+		filterPath := profile.Seccomp
+		if !filepath.IsAbs(filterPath) {
+			filterPath = filepath.Join(filepath.Dir(path), filterPath)
+		}
+		if profile.filter, err = loadFilter(filterPath); err != nil {
+			return nil, fmt.Errorf("invalid profile %s: %s", path, err)
+		}
+	}
+	return profile, nil
+}
+
+// Name returns the name of the profile.
+func (profile *Profile) Name() string {
+	return strings.TrimSuffix(filepath.Base(profile.Path), profileExt)
+}
+
+// Apply restricts the current thread, and the processes it starts, according
+// to the profile. The thread must be locked, and must not be used for anything
+// else afterwards, since the restrictions cannot be lifted.
+//
+// The seccomp filter is installed last, so it must allow the system calls
+// needed to start the command, such as execve.
+func (profile *Profile) Apply() error {
+	if profile.Capabilities != nil {
+		keep, err := capabilities(profile.Capabilities)
+		if err != nil {
+			return err
+		}
+		if err := dropBoundingSet(keep); err != nil {
+			return err
+		}
+	}
+	if profile.NoNewPrivs {
+		if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+			return fmt.Errorf("unable to set no_new_privs: %s", err)
+		}
+	}
+	if profile.filter != nil {
+		if err := installFilter(profile.filter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
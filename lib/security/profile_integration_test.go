@@ -0,0 +1,74 @@
+// +build integration
+
+package security
+
+import (
+	"encoding/binary"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// denyMkdir returns a seccomp filter failing mkdir and mkdirat with EPERM on
+// x86_64, and allowing every other system call.
+func denyMkdir() []byte {
+	instructions := [][4]uint32{
+		{0x20, 0, 0, 4},                 // load the architecture
+		{0x15, 1, 0, 0xc000003e},        // skip the next instruction on x86_64
+		{0x06, 0, 0, 0x7fff0000},        // allow
+		{0x20, 0, 0, 0},                 // load the system call number
+		{0x15, 2, 0, 83},                // mkdir
+		{0x15, 1, 0, 258},               // mkdirat
+		{0x06, 0, 0, 0x7fff0000},        // allow
+		{0x06, 0, 0, 0x00050000 | 0x01}, // fail with EPERM
+	}
+	data := []byte{}
+	for _, ins := range instructions {
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint16(b, uint16(ins[0]))
+		b[2], b[3] = byte(ins[1]), byte(ins[2])
+		binary.LittleEndian.PutUint32(b[4:], ins[3])
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestApply(t *testing.T) {
+	if runtime.GOARCH != "amd64" {
+		t.Skip("the test filter is only written for x86_64")
+	}
+	filter, err := parseFilter(denyMkdir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := &Profile{Capabilities: []string{"CAP_CHOWN"}, NoNewPrivs: true, filter: filter}
+
+	type result struct {
+		output string
+		err    error
+	}
+	dir := t.TempDir()
+	done := make(chan result)
+	go func() {
+		// The thread stays restricted, so it is never unlocked.
+		runtime.LockOSThread()
+		if err := profile.Apply(); err != nil {
+			done <- result{err: err}
+			return
+		}
+		script := "grep -E 'CapBnd|NoNewPrivs' /proc/self/status; mkdir " + dir + "/dir 2>&1 || true"
+		output, err := exec.Command("sh", "-c", script).Output()
+		done <- result{string(output), err}
+	}()
+	r := <-done
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	for _, expected := range []string{"CapBnd:\t0000000000000001", "NoNewPrivs:\t1", "Operation not permitted"} {
+		if !strings.Contains(r.output, expected) {
+			t.Errorf("expected output to contain %q, got: %s", expected, r.output)
+		}
+	}
+}
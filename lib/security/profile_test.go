@@ -0,0 +1,86 @@
+// +build unit
+
+package security
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProfilePath(t *testing.T) {
+	path, err := ProfilePath("/etc/rjob/profiles", "restricted")
+	if err != nil || path != "/etc/rjob/profiles/restricted.json" {
+		t.Errorf("expected: /etc/rjob/profiles/restricted.json, got: %s (%v)", path, err)
+	}
+	for _, name := range []string{"", "../etc/passwd", "a/b", ".hidden"} {
+		if _, err := ProfilePath("/etc/rjob/profiles", name); err == nil {
+			t.Errorf("error expected for profile name %q, but was not received", name)
+		}
+	}
+	if _, err := ProfilePath("", "restricted"); err == nil {
+		t.Errorf("error expected without a profile directory, but was not received")
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	numbers, err := capabilities([]string{"CAP_CHOWN", "net_bind_service"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(numbers) != 2 || !numbers[0] || !numbers[10] {
+		t.Errorf("expected capabilities 0 and 10, got: %v", numbers)
+	}
+	if _, err := capabilities([]string{"CAP_FLY"}); err == nil {
+		t.Errorf("error expected for unknown capability, but was not received")
+	}
+}
+
+func TestParseFilter(t *testing.T) {
+	// ret SECCOMP_RET_ALLOW
+	allow := []byte{0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x7f}
+	filter, err := parseFilter(allow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(filter) != 1 || filter[0].Code != 0x06 || filter[0].K != 0x7fff0000 {
+		t.Errorf("unexpected filter: %+v", filter)
+	}
+
+	if _, err := parseFilter(allow[:5]); err == nil {
+		t.Errorf("error expected for a truncated filter, but was not received")
+	}
+	if _, err := parseFilter(nil); err == nil {
+		t.Errorf("error expected for an empty filter, but was not received")
+	}
+	if _, err := parseFilter(make([]byte, 8*4097)); err == nil {
+		t.Errorf("error expected for a filter that is too long, but was not received")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	allow := []byte{0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0xff, 0x7f}
+	if err := os.WriteFile(filepath.Join(dir, "allow.bpf"), allow, 0600); err != nil {
+		t.Fatal(err)
+	}
+	data := `{"capabilities": ["CAP_CHOWN"], "no_new_privs": true, "seccomp": "allow.bpf"}`
+	if err := os.WriteFile(filepath.Join(dir, "restricted.json"), []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := Load(filepath.Join(dir, "restricted.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Name() != "restricted" || !profile.NoNewPrivs || len(profile.filter) != 1 {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "bad.json"), []byte(`{"capabilities": ["CAP_FLY"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(filepath.Join(dir, "bad.json")); err == nil {
+		t.Errorf("error expected for unknown capability, but was not received")
+	}
+}
@@ -0,0 +1,66 @@
+package security
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// Seccomp filters are made of 8 byte instructions, and the kernel accepts at
+// most 4096 of them.
+const (
+	filterInstructionSize = 8
+	maxFilterInstructions = 4096
+)
+
+// loadFilter reads a seccomp BPF filter. The file holds the instructions of the
+// filter as struct sock_filter, in the byte order of the host.
+func loadFilter(path string) ([]unix.SockFilter, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading seccomp filter: %s", err)
+	}
+	return parseFilter(data)
+}
+
+// parseFilter parses the instructions of a seccomp BPF filter.
+func parseFilter(data []byte) ([]unix.SockFilter, error) {
+	if len(data) == 0 || len(data)%filterInstructionSize != 0 {
+		return nil, fmt.Errorf("seccomp filter must be a multiple of %d bytes, got %d", filterInstructionSize, len(data))
+	}
+	if len(data)/filterInstructionSize > maxFilterInstructions {
+		return nil, fmt.Errorf("seccomp filter has more than %d instructions", maxFilterInstructions)
+	}
+
+	// The filter is read in the byte order of the host.
+	order := binary.ByteOrder(binary.LittleEndian)
+	if one := uint16(1); *(*byte)(unsafe.Pointer(&one)) == 0 {
+		order = binary.BigEndian
+	}
+	filter := make([]unix.SockFilter, 0, len(data)/filterInstructionSize)
+	for i := 0; i < len(data); i += filterInstructionSize {
+		filter = append(filter, unix.SockFilter{
+			Code: order.Uint16(data[i:]),
+			Jt:   data[i+2],
+			Jf:   data[i+3],
+			K:    order.Uint32(data[i+4:]),
+		})
+	}
+	return filter, nil
+}
+
+// installFilter installs a seccomp filter on the current thread.
+func installFilter(filter []unix.SockFilter) error {
+	program := unix.SockFprog{
+		Len:    uint16(len(filter)),
+		Filter: &filter[0],
+	}
+	err := unix.Prctl(unix.PR_SET_SECCOMP, unix.SECCOMP_MODE_FILTER, uintptr(unsafe.Pointer(&program)), 0, 0)
+	if err != nil {
+		return fmt.Errorf("unable to install seccomp filter: %s", err)
+	}
+	return nil
+}
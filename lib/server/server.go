@@ -3,12 +3,21 @@ package server
 import (
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"io/ioutil"
 	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/bill-rich/rjob/lib/api"
 	"github.com/bill-rich/rjob/lib/cgroup"
 	"github.com/bill-rich/rjob/lib/command"
+	"github.com/bill-rich/rjob/lib/network"
+	"github.com/bill-rich/rjob/lib/policy"
+	"github.com/bill-rich/rjob/lib/security"
+	"github.com/bill-rich/rjob/lib/store"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -21,14 +30,147 @@ type ServerConfig struct {
 
 	ListenAddress string
 	ListenPort    string
+
+	// StateDir is where jobs are saved so they survive restarts. Jobs are only
+	// kept in memory when it is empty.
+	StateDir string
+	// Retention is how long jobs are kept after they have ended. Jobs are
+	// kept forever when it is zero.
+	Retention time.Duration
+	// OutputLimits bound the output kept for each stream of a job. Output
+	// that does not fit in memory is kept in StateDir, or dropped when it is
+	// empty.
+	OutputLimits command.OutputLimits
+	// DegradeLimits starts jobs without the limits the host cannot enforce,
+	// instead of refusing them.
+	DegradeLimits bool
+
+	// PolicyFile holds the policy restricting what clients may ask for. The
+	// default policy is used when it is empty.
+	PolicyFile string
+	// ProfileDir holds the security profiles that the policy lets clients
+	// use.
+	ProfileDir string
+
+	// Rootless runs the server without privileges. Jobs are run in user
+	// namespaces, and the cgroup hierarchy must be delegated to the user
+	// running the server.
+	Rootless bool
+
+	// CgroupMode is either CgroupModePrivate or CgroupModeDelegated. It
+	// defaults to CgroupModeDelegated for rootless servers, and
+	// CgroupModePrivate otherwise.
+	CgroupMode string
+	// CgroupMountDir is where the private cgroup hierarchy is mounted.
+	CgroupMountDir string
+	// CgroupParent is the cgroup under which the cgroups of jobs are
+	// created. It is relative to the root of the private hierarchy, or to
+	// the server's own cgroup when delegated, and defaults to
+	// cgroup.DefaultParent or cgroup.DefaultSubtree respectively.
+	CgroupParent string
+}
+
+const (
+	// CgroupModePrivate mounts a new cgroup2 hierarchy for jobs.
+	CgroupModePrivate = "private"
+	// CgroupModeDelegated creates the cgroups of jobs under the server's own
+	// cgroup in the host's hierarchy, as delegated by systemd.
+	CgroupModeDelegated = "delegated"
+)
+
+// setupCgroups prepares the cgroup hierarchy that the cgroups of jobs are
+// created in.
+func (server *ServerConfig) setupCgroups() (*cgroup.Hierarchy, error) {
+	mode := server.CgroupMode
+	if mode == "" {
+		mode = CgroupModePrivate
+		if server.Rootless {
+			mode = CgroupModeDelegated
+		}
+	}
+
+	switch mode {
+	case CgroupModePrivate:
+		// Mounting a cgroup hierarchy requires root.
+		if server.Rootless {
+			return nil, fmt.Errorf("rootless servers require the %s cgroup mode", CgroupModeDelegated)
+		}
+		mountDir := server.CgroupMountDir
+		if mountDir == "" {
+			mountDir = cgroup.CgroupMountDir
+		}
+		return cgroup.MountPrivate(mountDir, server.CgroupParent)
+	case CgroupModeDelegated:
+		return cgroup.Delegated(server.CgroupParent)
+	}
+	return nil, fmt.Errorf("unknown cgroup mode: %s", mode)
+}
+
+// checkProfiles loads every security profile the policy lets clients use, so
+// that missing or invalid profiles are found before jobs need them.
+func (server *ServerConfig) checkProfiles(jobPolicy *policy.Policy) error {
+	rules := []policy.Rules{jobPolicy.Default}
+	for _, clientRules := range jobPolicy.Clients {
+		rules = append(rules, clientRules)
+	}
+	for _, r := range rules {
+		for _, name := range r.Profiles {
+			path, err := security.ProfilePath(server.ProfileDir, name)
+			if err != nil {
+				return err
+			}
+			if _, err := security.Load(path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 func (server *ServerConfig) StartServer() error {
 	log.SetLevel(log.DebugLevel)
 	log.Debugf("Starting job server at:%s", net.JoinHostPort(server.ListenAddress, server.ListenPort))
 
-	cgroup.Mount(cgroup.CgroupMountDir)
-	defer cgroup.Umount(cgroup.CgroupMountDir)
+	jobPolicy := policy.Default()
+	if server.PolicyFile != "" {
+		var err error
+		if jobPolicy, err = policy.Load(server.PolicyFile); err != nil {
+			return err
+		}
+	} else if server.Rootless {
+		// Only root is mapped in the user namespaces of rootless jobs, and
+		// it is the unprivileged user running the server.
+		jobPolicy.Default.Users = []string{"root"}
+	}
+
+	if err := server.checkProfiles(jobPolicy); err != nil {
+		return err
+	}
+
+	cgroups, err := server.setupCgroups()
+	if err != nil {
+		return err
+	}
+	defer cgroups.Close()
+	controllers, err := cgroups.EnableControllers()
+	if err != nil {
+		return err
+	}
+
+	// Creating the bridge requires root, so rootless servers cannot offer
+	// bridge networking.
+	var bridge *network.Bridge
+	if !server.Rootless {
+		if bridge, err = network.SetupBridge(); err != nil {
+			log.Warnf("Bridge networking is unavailable: %s", err)
+		} else {
+			defer func() {
+				if err := bridge.Close(); err != nil {
+					log.Errorf("Unable to tear down bridge: %s", err)
+				}
+			}()
+		}
+	}
 
 	// TODO: Use common.GetCreds() function.
 	certificate, err := tls.LoadX509KeyPair(server.CertLocation, server.KeyLocation)
@@ -62,8 +204,40 @@ func (server *ServerConfig) StartServer() error {
 	grpcServer := grpc.NewServer(serverOption)
 
 	jobServer := api.ApiServer{
-		Jobs: map[string]command.JobConfig{},
+		Jobs:          map[string]*command.JobConfig{},
+		OutputLimits:  server.OutputLimits,
+		CgroupParent:  cgroups.Parent,
+		Controllers:   controllers,
+		DegradeLimits: server.DegradeLimits,
+		Rootless:      server.Rootless,
+		Policy:        jobPolicy,
+		Bridge:        bridge,
+		ProfileDir:    server.ProfileDir,
+	}
+	if server.StateDir != "" {
+		jobStore, err := store.NewFileStore(server.StateDir)
+		if err != nil {
+			return err
+		}
+		jobServer.Store = jobStore
 	}
+	if err := jobServer.RestoreJobs(); err != nil {
+		return err
+	}
+	if err := jobServer.RemoveOrphanedCgroups(); err != nil {
+		log.Errorf("Unable to remove orphaned cgroups: %s", err)
+	}
+	go jobServer.ReapJobs(server.Retention)
+
+	// The server is torn down once serving stops, which happens when it is
+	// asked to terminate.
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-stop
+		log.Infof("Stopping job server on %s", sig)
+		grpcServer.Stop()
+	}()
 
 	api.RegisterJobsServer(grpcServer, &jobServer)
 	log.Debugf("Job server started successfully")
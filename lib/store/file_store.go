@@ -0,0 +1,105 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bill-rich/rjob/lib/command"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	recordFile = "job.json"
+	dirMode    = 0700
+	fileMode   = 0600
+)
+
+// FileStore is a JobStore keeping each job in its own directory. The record of
+// the job is saved as JSON, and each output stream is appended to a separate
+// file.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore returns a FileStore saving jobs under dir. The directory is
+// created if it does not exist.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return nil, fmt.Errorf("error creating job store directory %s: %s", dir, err)
+	}
+	return &FileStore{Dir: dir}, nil
+}
+
+// Save writes the record of a job. The record is written to a temporary file
+// first, so a crash never leaves a partially written record behind.
+func (fs *FileStore) Save(record JobRecord) error {
+	jobDir := filepath.Join(fs.Dir, record.Id)
+	if err := os.MkdirAll(jobDir, dirMode); err != nil {
+		return fmt.Errorf("error creating job directory %s: %s", jobDir, err)
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("error encoding job %s: %s", record.Id, err)
+	}
+
+	recordPath := filepath.Join(jobDir, recordFile)
+	tmpPath := recordPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, fileMode); err != nil {
+		return fmt.Errorf("error writing %s: %s", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, recordPath); err != nil {
+		return fmt.Errorf("error replacing %s: %s", recordPath, err)
+	}
+	return nil
+}
+
+// Load reads the records of all jobs in the store. Directories without a
+// readable record are skipped.
+func (fs *FileStore) Load() ([]JobRecord, error) {
+	entries, err := os.ReadDir(fs.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading job store directory %s: %s", fs.Dir, err)
+	}
+
+	records := []JobRecord{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		recordPath := filepath.Join(fs.Dir, entry.Name(), recordFile)
+		data, err := os.ReadFile(recordPath)
+		if err != nil {
+			log.Warnf("Skipping job %s: %s", entry.Name(), err)
+			continue
+		}
+		record := JobRecord{}
+		if err := json.Unmarshal(data, &record); err != nil {
+			log.Warnf("Skipping job %s: error decoding %s: %s", entry.Name(), recordPath, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// OpenOutput returns the output file of a stream. The file is created once
+// output is written to it.
+func (fs *FileStore) OpenOutput(jobId string, stream Stream) (command.OutputLog, error) {
+	jobDir := filepath.Join(fs.Dir, jobId)
+	if err := os.MkdirAll(jobDir, dirMode); err != nil {
+		return nil, fmt.Errorf("error creating job directory %s: %s", jobDir, err)
+	}
+	return &outputFile{path: filepath.Join(jobDir, string(stream))}, nil
+}
+
+// Delete removes the directory of a job.
+func (fs *FileStore) Delete(jobId string) error {
+	jobDir := filepath.Join(fs.Dir, jobId)
+	if err := os.RemoveAll(jobDir); err != nil {
+		return fmt.Errorf("error removing job directory %s: %s", jobDir, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+// +build unit
+
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/bill-rich/rjob/lib/command"
+)
+
+func TestFileStoreRecords(t *testing.T) {
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := JobRecord{
+		Id:       "testJob",
+		Owner:    "testUser",
+		Command:  "echo",
+		Args:     []string{"test"},
+		Status:   command.JobStatusRunning,
+		ExitCode: 0,
+	}
+	if err := fs.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	record.Status = command.JobStatusExited
+	record.ExitCode = 1
+	if err := fs.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// Directories without a record should be ignored.
+	if err := os.Mkdir(filepath.Join(fs.Dir, "notAJob"), dirMode); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := fs.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 record, got %d", len(records))
+	}
+	if !reflect.DeepEqual(records[0], record) {
+		t.Errorf("unexpected record. expected: %+v, got: %+v", record, records[0])
+	}
+}
+
+func TestFileStoreOutput(t *testing.T) {
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := fs.OpenOutput("testJob", Stdout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range []string{"first ", "second"} {
+		if _, err := output.Write([]byte(chunk)); err != nil {
+			t.Fatal(err)
+		}
+		output.Close()
+	}
+
+	data := make([]byte, 6)
+	if _, err := output.ReadAt(data, 6); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "second" {
+		t.Errorf("unexpected stdout. expected: second, got: %s", data)
+	}
+
+	if err := output.Discard(6); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := output.Write([]byte(" third")); err != nil {
+		t.Fatal(err)
+	}
+	output.Close()
+	saved, err := os.ReadFile(filepath.Join(fs.Dir, "testJob", string(Stdout)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != "second third" {
+		t.Errorf("unexpected stdout. expected: second third, got: %s", saved)
+	}
+
+	output, err = fs.OpenOutput("testJob", Stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size, err := output.Size(); err != nil || size != 0 {
+		t.Errorf("unexpected stderr. expected no output, got: %d bytes (%v)", size, err)
+	}
+}
+
+func TestFileStoreDelete(t *testing.T) {
+	fs, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fs.Save(JobRecord{Id: "testJob"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Delete("testJob"); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := fs.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no records, got %d", len(records))
+	}
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// outputFile is the saved output of a stream. It is only kept open while
+// output is written to it, so that the output of ended jobs does not hold on
+// to file descriptors.
+type outputFile struct {
+	path string
+	f    *os.File
+}
+
+// Write appends to the file, opening it on the first write.
+func (o *outputFile) Write(data []byte) (int, error) {
+	if o.f == nil {
+		f, err := os.OpenFile(o.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, fileMode)
+		if err != nil {
+			return 0, fmt.Errorf("error opening %s: %s", o.path, err)
+		}
+		o.f = f
+	}
+	return o.f.Write(data)
+}
+
+// ReadAt reads the output at offset. A file that does not exist yet holds no
+// output.
+func (o *outputFile) ReadAt(data []byte, offset int64) (int, error) {
+	f, err := os.Open(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, io.EOF
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error opening %s: %s", o.path, err)
+	}
+	defer f.Close()
+	return f.ReadAt(data, offset)
+}
+
+// Size returns the size of the file.
+func (o *outputFile) Size() (int64, error) {
+	info, err := os.Stat(o.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading %s: %s", o.path, err)
+	}
+	return info.Size(), nil
+}
+
+// Discard drops the first n bytes of the file. The rest is copied to a new file
+// that replaces the old one.
+func (o *outputFile) Discard(n int64) error {
+	src, err := os.Open(o.path)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %s", o.path, err)
+	}
+	defer src.Close()
+	if _, err := src.Seek(n, io.SeekStart); err != nil {
+		return fmt.Errorf("error reading %s: %s", o.path, err)
+	}
+
+	tmpPath := o.path + ".tmp"
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, fileMode)
+	if err != nil {
+		return fmt.Errorf("error opening %s: %s", tmpPath, err)
+	}
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("error writing %s: %s", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, o.path); err != nil {
+		return fmt.Errorf("error replacing %s: %s", o.path, err)
+	}
+
+	// Later output is appended to the new file.
+	return o.Close()
+}
+
+// Close closes the file until output is written again.
+func (o *outputFile) Close() error {
+	if o.f == nil {
+		return nil
+	}
+	err := o.f.Close()
+	o.f = nil
+	return err
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"time"
+
+	"github.com/bill-rich/rjob/lib/cgroup"
+	"github.com/bill-rich/rjob/lib/command"
+)
+
+// Stream identifies one of the output streams of a job.
+type Stream string
+
+const (
+	Stdout Stream = "stdout"
+	Stderr Stream = "stderr"
+	// Records holds the output of both streams framed into timestamped
+	// lines, for jobs started with timestamps.
+	Records Stream = "records"
+)
+
+// JobRecord holds everything about a job that is kept across restarts of the
+// rjob server.
+type JobRecord struct {
+	Id         string       `json:"id"`
+	Owner      string       `json:"owner"`
+	Command    string       `json:"command"`
+	Args       []string     `json:"args"`
+	Tty        bool         `json:"tty"`
+	Timestamps bool         `json:"timestamps,omitempty"`
+	Status     string       `json:"status"`
+	ExitCode   int          `json:"exit_code"`
+	Signal     string       `json:"signal,omitempty"`
+	StartTime  time.Time    `json:"start_time"`
+	EndTime    time.Time    `json:"end_time"`
+	StartError string       `json:"start_error,omitempty"`
+	Usage      cgroup.Usage `json:"usage"`
+	// OutputStart is the offset in each stream of the first byte of saved
+	// output. Older output was dropped to keep only the most recent output.
+	OutputStart map[Stream]int64 `json:"output_start,omitempty"`
+}
+
+// JobStore persists jobs and their output so that they survive restarts of the
+// rjob server.
+type JobStore interface {
+	// Save creates or replaces the record of a job.
+	Save(record JobRecord) error
+	// Load returns the records of all saved jobs.
+	Load() ([]JobRecord, error)
+	// OpenOutput returns the saved output of a job. New output is appended
+	// to it.
+	OpenOutput(jobId string, stream Stream) (command.OutputLog, error)
+	// Delete removes a job and its output.
+	Delete(jobId string) error
+}
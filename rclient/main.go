@@ -2,16 +2,27 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	arg "github.com/alexflint/go-arg"
 	"github.com/bill-rich/rjob/lib/api"
+	"github.com/bill-rich/rjob/lib/cgroup"
 	"github.com/bill-rich/rjob/lib/common"
+	"github.com/bill-rich/rjob/lib/network"
+	"github.com/bill-rich/rjob/lib/rootfs"
+	"golang.org/x/term"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type ClientConfig struct {
@@ -28,12 +39,36 @@ type ClientConfig struct {
 var args struct {
 	Operation   string   `arg:"positional,required"`
 	Target      string   `arg:"required"`
-	CpuLimit    int      `default:"100"`
-	MemoryLimit int      `default:"0"`
-	IoLimit     int      `default:"100"`
+	CpuLimit    *int     `arg:"--cpu"`
+	MemoryLimit *int     `arg:"--memory"`
+	MemoryHigh  *int     `arg:"--memoryhigh"`
+	MemorySwap  int      `arg:"--memoryswap"`
+	IoLimit     *int     `arg:"--io"`
+	IoMax       []string `arg:"--iomax"`
+	Pids        int
+	Cpus        string
+	Mems        string
+	Ns          []string
+	Network     string
+	Publish     []string
+	Rootfs      []string
+	Mount       []string
+	User        string
+	Group       string
+	Groups      []string
+	Profile     string
 	Command     string   `arg:"positional"`
 	Args        []string `arg:"positional"`
 	JobId       string
+	Tty         bool
+	Stdin       bool
+	Tail        uint32
+	SinceOffset []uint64 `arg:"--since-offset"`
+	NoFollow    bool     `arg:"--no-follow"`
+	Timestamps  bool
+	JSON        bool   `arg:"--json"`
+	Signal      string `default:"SIGTERM"`
+	Grace       *int
 }
 
 func main() {
@@ -56,23 +91,84 @@ func main() {
 		client.start()
 	case "stop":
 		client.stop()
+	case "update":
+		client.update()
 	case "list":
 		client.list()
 	case "status":
 		client.status()
 	case "monitor":
 		client.monitor()
+	case "attach":
+		client.attach()
 	}
 
 }
 
 func (client *ClientConfig) start() {
 	input := &api.StartJobInput{
-		Command: args.Command,
-		Args:    args.Args,
-		Cpu:     int32(args.CpuLimit),
-		Memory:  int32(args.MemoryLimit),
-		Blkio:   int32(args.IoLimit),
+		Command:    args.Command,
+		Args:       args.Args,
+		Cpu:        int32(limitOr(args.CpuLimit, 100)),
+		Memory:     int32(limitOr(args.MemoryLimit, 0)),
+		MemoryHigh: int32(limitOr(args.MemoryHigh, 0)),
+		Blkio:      int32(limitOr(args.IoLimit, 100)),
+		MemorySwap: int32(args.MemorySwap),
+		Pids:       int32(args.Pids),
+		CpusetCpus: args.Cpus,
+		CpusetMems: args.Mems,
+		Tty:        args.Tty,
+		Stdin:      args.Stdin,
+		Network:    args.Network,
+		Rootfs:     args.Rootfs,
+		User:       args.User,
+		Group:      args.Group,
+		Groups:     args.Groups,
+		Profile:    args.Profile,
+		Timestamps: args.Timestamps,
+	}
+	if args.Ns != nil {
+		input.Namespaces = &api.Namespaces{Names: args.Ns}
+		// Jobs in no namespaces are selected with --ns none.
+		if len(args.Ns) == 1 && args.Ns[0] == "none" {
+			input.Namespaces.Names = nil
+		}
+	}
+	for _, limit := range args.IoMax {
+		ioMax, err := cgroup.ParseIoMax(limit)
+		if err != nil {
+			log.Fatal(err)
+		}
+		input.IoMax = append(input.IoMax, &api.IoMax{
+			Major: ioMax.Major,
+			Minor: ioMax.Minor,
+			Rbps:  ioMax.Rbps,
+			Wbps:  ioMax.Wbps,
+			Riops: ioMax.Riops,
+			Wiops: ioMax.Wiops,
+		})
+	}
+	for _, publish := range args.Publish {
+		port, err := network.ParsePortForward(publish)
+		if err != nil {
+			log.Fatal(err)
+		}
+		input.Ports = append(input.Ports, &api.PortForward{
+			HostPort: uint32(port.HostPort),
+			JobPort:  uint32(port.JobPort),
+			Protocol: port.Protocol,
+		})
+	}
+	for _, value := range args.Mount {
+		mount, err := rootfs.ParseMount(value)
+		if err != nil {
+			log.Fatal(err)
+		}
+		input.Mounts = append(input.Mounts, &api.Mount{
+			Source:   mount.Source,
+			Target:   mount.Target,
+			ReadOnly: mount.ReadOnly,
+		})
 	}
 	jobId, err := client.jobs.Start(context.TODO(), input)
 	if err != nil {
@@ -81,19 +177,62 @@ func (client *ClientConfig) start() {
 	fmt.Println(jobId.JobId)
 }
 
+// limitOr returns the limit if it was given, and def otherwise. Limits are
+// pointers so that update can tell which were given, so the defaults for start
+// are applied here.
+func limitOr(limit *int, def int) int {
+	if limit == nil {
+		return def
+	}
+	return *limit
+}
+
+// optionalLimit converts a limit that may not have been given for the request.
+func optionalLimit(limit *int) *int32 {
+	if limit == nil {
+		return nil
+	}
+	converted := int32(*limit)
+	return &converted
+}
+
+func (client *ClientConfig) update() {
+	if args.JobId == "" {
+		log.Fatal("job ID required to update")
+		return
+	}
+	input := &api.UpdateLimitsInput{
+		JobId:      args.JobId,
+		Cpu:        optionalLimit(args.CpuLimit),
+		Memory:     optionalLimit(args.MemoryLimit),
+		MemoryHigh: optionalLimit(args.MemoryHigh),
+		Blkio:      optionalLimit(args.IoLimit),
+	}
+	resp, err := client.jobs.UpdateLimits(context.TODO(), input)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	fmt.Printf("cpu:%d\tmemory:%d\tmemory_high:%d\tio:%d\n", resp.Cpu, resp.Memory, resp.MemoryHigh, resp.Blkio)
+}
+
 func (client *ClientConfig) stop() {
 	if args.JobId == "" {
 		log.Fatal("job ID required to stop")
 		return
 	}
 	input := &api.StopJobInput{
-		JobId: args.JobId,
+		JobId:       args.JobId,
+		Signal:      args.Signal,
+		GracePeriod: optionalLimit(args.Grace),
 	}
 	resp, err := client.jobs.Stop(context.TODO(), input)
 	if err != nil {
 		log.Fatal(err)
 		return
 	}
+	fmt.Printf("%s\texit_code:%d%s\n", resp.Status, resp.ExitCode,
+		formatState(resp.Signal, resp.StartTime, resp.EndTime, resp.StartError))
 	os.Exit(int(resp.ExitCode))
 }
 
@@ -104,7 +243,8 @@ func (client *ClientConfig) list() {
 		return
 	}
 	for _, jobInfo := range resp.JobInfo {
-		fmt.Printf("%s:\t%s\texit_code:%d\n", jobInfo.TaskId, jobInfo.Status, jobInfo.ExitCode)
+		fmt.Printf("%s:\t%s\texit_code:%d%s\n", jobInfo.TaskId, jobInfo.Status, jobInfo.ExitCode,
+			formatState(jobInfo.Signal, jobInfo.StartTime, jobInfo.EndTime, jobInfo.StartError))
 	}
 }
 
@@ -117,12 +257,57 @@ func (client *ClientConfig) status() {
 		fmt.Println(err)
 		return
 	}
-	fmt.Printf("%s\texit_code:%d", resp.Status, resp.ExitCode)
+	fmt.Printf("%s\texit_code:%d%s%s\n", resp.Status, resp.ExitCode,
+		formatState(resp.Signal, resp.StartTime, resp.EndTime, resp.StartError),
+		formatUsage(resp.Usage))
+}
+
+// formatUsage formats the resources used by a job. CPU time is printed as a
+// duration, and memory and io in bytes.
+func formatUsage(usage *api.JobUsage) string {
+	if usage == nil {
+		return ""
+	}
+	return fmt.Sprintf("\tcpu:%s\tuser:%s\tsystem:%s\tmemory:%d\tmemory_peak:%d\toom_kills:%d\tread_bytes:%d\twrite_bytes:%d",
+		time.Duration(usage.CpuUsec)*time.Microsecond,
+		time.Duration(usage.UserUsec)*time.Microsecond,
+		time.Duration(usage.SystemUsec)*time.Microsecond,
+		usage.MemoryCurrent, usage.MemoryPeak, usage.OomKills, usage.ReadBytes, usage.WriteBytes)
+}
+
+// formatState formats the details of a job's state that are only known for
+// some jobs.
+func formatState(signal string, startTime, endTime *timestamppb.Timestamp, startError string) string {
+	state := ""
+	if signal != "" {
+		state += fmt.Sprintf("\tsignal:%s", signal)
+	}
+	if startTime != nil {
+		state += fmt.Sprintf("\tstarted:%s", startTime.AsTime().Local().Format(time.RFC3339))
+	}
+	if endTime != nil {
+		state += fmt.Sprintf("\tended:%s", endTime.AsTime().Local().Format(time.RFC3339))
+	}
+	if startError != "" {
+		state += fmt.Sprintf("\terror:%s", startError)
+	}
+	return state
 }
 
 func (client *ClientConfig) monitor() {
+	if len(args.SinceOffset) > 2 {
+		log.Fatal("--since-offset takes the offset of stdout, and optionally of stderr")
+	}
+	offsets := [2]uint64{}
+	copy(offsets[:], args.SinceOffset)
+	follow := !args.NoFollow
 	input := &api.MonitorJobInput{
-		JobId: args.JobId,
+		JobId:        args.JobId,
+		Offset:       offsets[0],
+		StderrOffset: offsets[1],
+		TailLines:    args.Tail,
+		Follow:       &follow,
+		Records:      args.Timestamps || args.JSON,
 	}
 	monitorClient, err := client.jobs.Monitor(context.TODO(), input)
 	if err != nil {
@@ -134,10 +319,153 @@ func (client *ClientConfig) monitor() {
 			if err == io.EOF {
 				return
 			}
+			if status.Code(err) == codes.Unavailable {
+				// Monitoring can be resumed where it stopped.
+				log.Fatalf("%s (resume with --since-offset %d %d)", err, offsets[0], offsets[1])
+			}
 			log.Fatal(err)
 		}
-		fmt.Print(chunk.Chunk)
-		time.Sleep(1 * time.Second)
+		if input.Records {
+			printRecords(chunk)
+		} else {
+			printChunk(chunk)
+		}
+		offsets[chunk.Stream] = chunk.NextOffset
+	}
+}
+
+func (client *ClientConfig) attach() {
+	if args.JobId == "" {
+		log.Fatal("job ID required to attach")
+		return
+	}
+	attachClient, err := client.jobs.Attach(context.TODO())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := attachClient.Send(&api.AttachInput{JobId: args.JobId}); err != nil {
+		log.Fatal(err)
+	}
+
+	// Streams do not support concurrent sends, so all input is funneled
+	// through a single goroutine.
+	inputs := make(chan *api.AttachInput)
+	go sendInputs(attachClient, inputs)
+	go readStdin(inputs)
+
+	restore := func() {}
+	if args.Tty {
+		fd := int(os.Stdin.Fd())
+		oldState, err := term.MakeRaw(fd)
+		if err != nil {
+			log.Fatal(err)
+		}
+		restore = func() { term.Restore(fd, oldState) }
+		go watchWindowSize(inputs)
+	}
+
+	for {
+		chunk, err := attachClient.Recv()
+		if err != nil {
+			restore()
+			if err == io.EOF {
+				return
+			}
+			log.Fatal(err)
+		}
+		printChunk(chunk)
+	}
+}
+
+// sendInputs sends everything received on inputs to the attached job.
+func sendInputs(attachClient api.Jobs_AttachClient, inputs <-chan *api.AttachInput) {
+	for input := range inputs {
+		if err := attachClient.Send(input); err != nil {
+			return
+		}
+	}
+}
+
+// readStdin forwards the local stdin to the attached job. The job's stdin is
+// closed once the local stdin reaches EOF.
+func readStdin(inputs chan<- *api.AttachInput) {
+	for {
+		buf := make([]byte, 32*1024)
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			inputs <- &api.AttachInput{Stdin: buf[:n]}
+		}
+		if err != nil {
+			inputs <- &api.AttachInput{CloseStdin: true}
+			return
+		}
+	}
+}
+
+// watchWindowSize sends the size of the local terminal to the attached job, and
+// sends it again every time the terminal is resized.
+func watchWindowSize(inputs chan<- *api.AttachInput) {
+	resized := make(chan os.Signal, 1)
+	signal.Notify(resized, syscall.SIGWINCH)
+	for {
+		cols, rows, err := term.GetSize(int(os.Stdin.Fd()))
+		if err == nil {
+			inputs <- &api.AttachInput{
+				WindowSize: &api.WindowSize{Rows: uint32(rows), Cols: uint32(cols)},
+			}
+		}
+		<-resized
+	}
+}
+
+// printChunk writes a chunk of job output to the local stream it came from.
+func printChunk(chunk *api.MonitorJobResponse) {
+	printSkipped(chunk)
+	if chunk.Stream == api.OutputStream_STDERR {
+		os.Stderr.Write(chunk.Chunk)
+	} else {
+		os.Stdout.Write(chunk.Chunk)
+	}
+}
+
+// printRecords writes records of job output. With --json, each record is
+// written to stdout as a JSON object. Otherwise each line is written to the
+// local stream it came from, after the time it was received.
+func printRecords(chunk *api.MonitorJobResponse) {
+	printSkipped(chunk)
+	for _, record := range chunk.Records {
+		if args.JSON {
+			json.NewEncoder(os.Stdout).Encode(outputRecord{
+				Sequence: record.Sequence,
+				Time:     record.Time.AsTime(),
+				Stream:   strings.ToLower(record.Stream.String()),
+				Line:     string(record.Line),
+			})
+			continue
+		}
+		w := os.Stdout
+		if record.Stream == api.OutputStream_STDERR {
+			w = os.Stderr
+		}
+		line := append([]byte(record.Time.AsTime().Local().Format(time.RFC3339Nano)+" "), record.Line...)
+		w.Write(append(line, '\n'))
+	}
+}
+
+// outputRecord is how --json prints a record. Lines that are not valid UTF-8
+// cannot be represented in JSON, so invalid bytes are replaced.
+type outputRecord struct {
+	Sequence uint64    `json:"seq"`
+	Time     time.Time `json:"time"`
+	Stream   string    `json:"stream"`
+	Line     string    `json:"line"`
+}
+
+// printSkipped tells the user about output that was no longer kept by the
+// server.
+func printSkipped(chunk *api.MonitorJobResponse) {
+	if chunk.Skipped > 0 {
+		fmt.Fprintf(os.Stderr, "rclient: %d bytes of output were no longer kept by the server\n", chunk.Skipped)
 	}
 }
 
@@ -1,20 +1,52 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
 
 	arg "github.com/alexflint/go-arg"
+	"github.com/bill-rich/rjob/lib/cgroup"
 	"github.com/bill-rich/rjob/lib/command"
+	"github.com/bill-rich/rjob/lib/network"
+	"github.com/bill-rich/rjob/lib/rootfs"
+	"github.com/bill-rich/rjob/lib/security"
 	"github.com/bill-rich/rjob/lib/server"
 )
 
 var args struct {
-	Operation     string   `arg:"positional,required"`
-	ListenAddress string   `default:"0.0.0.0"`
-	ListenPort    string   `default:"9080"`
+	Operation     string `arg:"positional,required"`
+	ListenAddress string `default:"0.0.0.0"`
+	ListenPort    string `default:"9080"`
+	StateDir      string
+	Retention     time.Duration
+	OutputMemory  int64 `default:"4"`
+	OutputRetain  int64
+	DegradeLimits bool
+	Policy        string
+	Rootless      bool
+	CgroupMode    string
+	CgroupMount   string `default:"/tmp/rjob/cgroup"`
+	CgroupParent  string
 	Cgroup        string   `arg:"positional"`
 	Command       string   `arg:"positional"`
 	Args          []string `arg:"positional"`
+	Tty           bool
+	CgroupNs      bool
+	ReportFd      int
+	NetFd         int
+	Proc          bool
+	Uid           *uint32
+	Gid           *uint32
+	Groups        []uint32
+	Profile       string
+	Profiles      string
+	Rootfs        []string
+	Mount         []string
 }
 
 func main() {
@@ -25,16 +57,57 @@ func main() {
 			log.Fatalf("cgroup and command are required to call reexec")
 		}
 		job := command.JobConfig{
-			CgroupName: args.Cgroup,
-			Command:    args.Command,
-			Args:       args.Args,
+			CgroupName: filepath.Base(args.Cgroup),
+			CgroupConfig: &cgroup.CgroupConfig{
+				Name: filepath.Base(args.Cgroup),
+				Path: args.Cgroup,
+			},
+			Command: args.Command,
+			Args:    args.Args,
+			Stdin:   os.Stdin,
+			// The other namespaces were created along with the wrapper.
+			Namespaces: []string{},
+			MountProc:  args.Proc,
 		}
+		if args.Uid != nil && args.Gid != nil {
+			job.Credential = &syscall.Credential{Uid: *args.Uid, Gid: *args.Gid, Groups: args.Groups}
+		}
+		if args.Profile != "" {
+			// The profile is loaded before the wrapper pivots into the
+			// job's root filesystem.
+			profile, err := security.Load(args.Profile)
+			if err != nil {
+				log.Fatal(err)
+			}
+			job.Profile = profile
+		}
+		if args.CgroupNs {
+			job.Namespaces = []string{command.NamespaceCgroup}
+		}
+		if args.ReportFd != 0 {
+			// The job's command must not be able to send reports.
+			syscall.CloseOnExec(args.ReportFd)
+			job.Report = os.NewFile(uintptr(args.ReportFd), "report")
+		}
+		job.BeforeStart = prepare(args.NetFd, args.Rootfs, args.Mount)
+		// Signals are sent to every process of the job, and signals generated
+		// by a terminal reach the command directly. They are caught so that
+		// the wrapper keeps running until the command has exited.
+		signal.Notify(make(chan os.Signal, 1))
+		// The command writes straight to the pipes or terminal of the
+		// wrapper, so its output is only kept by the server.
+		job.Stdout = os.Stdout
+		job.Stderr = os.Stderr
 		if err := job.Run(); err != nil {
 			log.Fatal(err)
 		}
-
-		job.PrintJobOutput()
+		job.Wait()
+		os.Exit(job.State().ExitCode)
 	case "start":
+		stateDir, err := defaultStateDir(args.StateDir, args.Rootless)
+		if err != nil {
+			log.Fatal(err)
+		}
 		server := server.ServerConfig{
 			CaLocation:   "/tmp/rjob/ssl/ca.crt",
 			CertLocation: "/tmp/rjob/ssl/server.crt",
@@ -42,6 +115,20 @@ func main() {
 
 			ListenAddress: args.ListenAddress,
 			ListenPort:    args.ListenPort,
+			StateDir:      stateDir,
+			Retention:     args.Retention,
+			OutputLimits: command.OutputLimits{
+				Memory: args.OutputMemory << 20,
+				Retain: args.OutputRetain << 20,
+			},
+			DegradeLimits: args.DegradeLimits,
+			PolicyFile:    args.Policy,
+			ProfileDir:    args.Profiles,
+
+			Rootless:       args.Rootless,
+			CgroupMode:     args.CgroupMode,
+			CgroupMountDir: args.CgroupMount,
+			CgroupParent:   args.CgroupParent,
 		}
 
 		if err := server.StartServer(); err != nil {
@@ -49,3 +136,56 @@ func main() {
 		}
 	}
 }
+
+// defaultStateDir returns the directory jobs are saved in when none was given.
+// Rootless servers cannot write to /var/lib, so they use the state directory of
+// the user running them instead.
+func defaultStateDir(dir string, rootless bool) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	if !rootless {
+		return "/var/lib/rjob", nil
+	}
+	if xdg := os.Getenv("XDG_STATE_HOME"); filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "rjob"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("unable to find a state directory for a rootless server, set --statedir: %s", err)
+	}
+	return filepath.Join(home, ".local", "state", "rjob"), nil
+}
+
+// prepare returns a function preparing the namespaces the wrapper was started
+// in before the job's command is run. The network is configured once the
+// server has sent its configuration, and the wrapper then pivots into the
+// job's root filesystem, if it has one.
+func prepare(netFd int, layers, mounts []string) func() error {
+	return func() error {
+		if netFd != 0 {
+			cfg, err := command.ReadNetworkConfig(os.NewFile(uintptr(netFd), "network"))
+			if err != nil {
+				return err
+			}
+			if err := network.Configure(cfg); err != nil {
+				return fmt.Errorf("unable to configure network: %s", err)
+			}
+		}
+		if len(layers) == 0 {
+			return nil
+		}
+		cfg := rootfs.Config{Layers: layers}
+		for _, value := range mounts {
+			mount, err := rootfs.ParseMount(value)
+			if err != nil {
+				return err
+			}
+			cfg.Mounts = append(cfg.Mounts, mount)
+		}
+		if err := rootfs.Setup(cfg); err != nil {
+			return fmt.Errorf("unable to set up root filesystem: %s", err)
+		}
+		return nil
+	}
+}